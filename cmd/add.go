@@ -21,6 +21,9 @@ func addCmd() *cobra.Command {
 				}
 				count = parsed
 			}
+			if err := noteFollowUp(); err != nil {
+				return err
+			}
 			client, err := getClient()
 			if err != nil {
 				return err
@@ -30,7 +33,7 @@ func addCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Printf("\u2705 Added %dx product %s to cart\n", count, args[0])
+			fmt.Println(msg("cart.added", count, args[0]))
 			showCartSummary(cart)
 			return nil
 		},
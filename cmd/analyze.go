@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -37,10 +36,16 @@ type categoryPreference struct {
 }
 
 func analyzeCmd() *cobra.Command {
+	var refreshCatalog bool
+	var rulesPath string
 	cmd := &cobra.Command{
 		Use:   "analyze-orders",
 		Short: "Analyze order history and infer preferences",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			rules, err := loadCategoryRules(rulesPath, currentCountry())
+			if err != nil {
+				return err
+			}
 			client, err := getClient()
 			if err != nil {
 				return err
@@ -51,15 +56,18 @@ func analyzeCmd() *cobra.Command {
 				return err
 			}
 			if len(products) == 0 {
-				fmt.Println("No products found in order history")
+				fmt.Println(msg("analyze.noProducts"))
 				return nil
 			}
 
-			categories, preferences, topProducts := analyzePreferences(products)
-			showAnalysis(categories, preferences, topProducts)
+			classifier := loadProductClassifier(client, refreshCatalog, rules)
+			categories, preferences, topProducts := analyzePreferences(products, classifier)
+			showAnalysis(categories, preferences, topProducts, rules)
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&rulesPath, "rules", "", "Category rules file (default ~/.picnic-rules.json or the built-in rules for PICNIC_COUNTRY)")
+	cmd.Flags().BoolVar(&refreshCatalog, "refresh-catalog", false, "Refresh the cached Picnic catalog used for categories")
 	return cmd
 }
 
@@ -67,13 +75,13 @@ func fetchAllOrders(client interface {
 	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
 	GetDelivery(deliveryId string) (*picnic.Delivery, error)
 }) ([]productEntry, error) {
-	fmt.Println("\U0001F4E6 Fetching order history...\n")
+	fmt.Printf("%s\n\n", msg("analyze.fetching"))
 
 	deliveries, err := client.GetDeliveries(nil)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Printf("Found %d deliveries\n\n", len(*deliveries))
+	fmt.Printf("%s\n\n", msg("analyze.found", len(*deliveries)))
 
 	var allProducts []productEntry
 	processed := 0
@@ -85,7 +93,7 @@ func fetchAllOrders(client interface {
 
 	for _, delivery := range recent {
 		processed++
-		fmt.Printf("\rProcessing %d/%d...", processed, len(recent))
+		fmt.Printf("\r%s", msg("analyze.processing", processed, len(recent)))
 
 		deliveryID := delivery.DeliveryId
 		if deliveryID == "" {
@@ -134,19 +142,20 @@ func fetchAllOrders(client interface {
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	fmt.Printf("\n\n\u2705 Extracted %d product entries\n", len(allProducts))
+	fmt.Printf("\n\n%s\n", msg("analyze.extracted", len(allProducts)))
+	recordPrices(deliveryObservations(allProducts))
 
 	historyPath, err := historyFilePath()
 	if err == nil {
 		if err := writeJSONFile(historyPath, allProducts); err == nil {
-			fmt.Printf("Saved to %s\n", historyPath)
+			fmt.Println(msg("analyze.saved", historyPath))
 		}
 	}
 
 	return allProducts, nil
 }
 
-func analyzePreferences(products []productEntry) (map[string][]productCount, map[string]categoryPreference, []productCount) {
+func analyzePreferences(products []productEntry, classifier *productClassifier) (map[string][]productCount, map[string]categoryPreference, []productCount) {
 	counts := map[string]*productCount{}
 	for _, p := range products {
 		entry, ok := counts[p.ID]
@@ -171,56 +180,15 @@ func analyzePreferences(products []productEntry) (map[string][]productCount, map
 		return sorted[i].Count > sorted[j].Count
 	})
 
-	categories := map[string][]productCount{
-		"melk":        {},
-		"boter":       {},
-		"brood":       {},
-		"kaas":        {},
-		"eieren":      {},
-		"yoghurt":     {},
-		"vleeswaren":  {},
-		"fruit":       {},
-		"groente":     {},
-		"vlees":       {},
-		"drank":       {},
-		"snoep":       {},
-		"diepvries":   {},
-		"overig":      {},
-	}
-
-	patterns := map[string]*regexp.Regexp{
-		"melk":       regexp.MustCompile("(?i)melk|milk|havermelk|amandel.*melk|soja.*melk|sojamelk|oatly|alpro|verse\\s?melk|volle\\s?melk|halfvolle\\s?melk"),
-		"boter":      regexp.MustCompile("(?i)boter|margarine|halvarine|becel|rama"),
-		"brood":      regexp.MustCompile("(?i)brood|bol(len)?|broodje|toast|croissant|baguette|ciabatta|pistolet|boterham"),
-		"kaas":       regexp.MustCompile("(?i)kaas|cheese|gouda|emmentaler|mozzarella|parmezaan|parmesan|feta|camembert|brie|plak"),
-		"eieren":     regexp.MustCompile("(?i)\\bei(er|ren)\\b|\\begg(s)?\\b|vrije\\s?uitloop|scharrel"),
-		"yoghurt":    regexp.MustCompile("(?i)yoghurt|yogurt|kwark|skyr|pudding|dessert"),
-		"vleeswaren": regexp.MustCompile("(?i)ham|salami|worst|vleeswaren|bacon|spek|mortadella|leverworst"),
-		"fruit":      regexp.MustCompile("(?i)appel|banaan|sinaasappel|peer|druif|bessen|mango|ananas|kiwi|citroen|limoen|avocado|meloen"),
-		"groente":    regexp.MustCompile("(?i)tomaat|komkommer|paprika|ui|wortel|sla|spinazie|broccoli|courgette|aardappel|champignon|prei"),
-		"vlees":      regexp.MustCompile("(?i)kip|kalf|rund|runder|varken|gehakt|filet|steak|schnitzel|goulash|shoarma"),
-		"drank":      regexp.MustCompile("(?i)water|sap|cola|limonade|fanta|sprite|bier|wijn|thee|koffie|energy|spa|frisdrank|sapjes"),
-		"snoep":      regexp.MustCompile("(?i)chocolade|koek|cookie|gummi|chips|snack|reep|ijs|bonbon|snoep"),
-		"diepvries":  regexp.MustCompile("(?i)diepvries|vries|pizza|patat|fri(et|t)en|vissticks|spinazie.*vries"),
-	}
-
+	categories := map[string][]productCount{}
 	for _, product := range sorted {
-		categorized := false
-		for cat, pattern := range patterns {
-			if pattern.MatchString(product.Name) {
-				categories[cat] = append(categories[cat], product)
-				categorized = true
-				break
-			}
-		}
-		if !categorized {
-			categories["overig"] = append(categories["overig"], product)
-		}
+		cat := classifier.classify(product.ID, product.Name)
+		categories[cat] = append(categories[cat], product)
 	}
 
 	preferences := map[string]categoryPreference{}
 	for cat, items := range categories {
-		if cat == "overig" || len(items) == 0 {
+		if cat == classifier.rules.other.Name || len(items) == 0 {
 			continue
 		}
 		pref := categoryPreference{
@@ -240,7 +208,7 @@ func analyzePreferences(products []productEntry) (map[string][]productCount, map
 	prefPath, err := preferencesFilePath()
 	if err == nil {
 		_ = writeJSONFile(prefPath, preferences)
-		fmt.Printf("\n\u2705 Saved preferences to %s\n", prefPath)
+		fmt.Printf("\n%s\n", msg("analyze.savedPreferences", prefPath))
 	}
 
 	topProducts := sorted
@@ -251,39 +219,29 @@ func analyzePreferences(products []productEntry) (map[string][]productCount, map
 	return categories, preferences, topProducts
 }
 
-func showAnalysis(categories map[string][]productCount, preferences map[string]categoryPreference, topProducts []productCount) {
+func showAnalysis(categories map[string][]productCount, preferences map[string]categoryPreference, topProducts []productCount, rules *categoryRules) {
 	fmt.Println("\n" + strings.Repeat("=", 60))
-	fmt.Println("\U0001F4CA JOUW WINKELGEWOONTES")
+	fmt.Println(msg("analyze.title"))
 	fmt.Println(strings.Repeat("=", 60))
 
-	fmt.Println("\n\U0001F3C6 TOP 15 MEEST GEKOCHTE PRODUCTEN:\n")
 	limit := 15
+	fmt.Printf("\n%s\n\n", msg("analyze.topProducts", limit))
 	if len(topProducts) < limit {
 		limit = len(topProducts)
 	}
 	for i := 0; i < limit; i++ {
 		p := topProducts[i]
-		price := formatPrice(p.Price)
+		price := amount(p.Price)
 		fmt.Printf("%2d. %s (%dx) %s\n", i+1, p.Name, p.Count, price)
 	}
 
 	fmt.Println("\n" + strings.Repeat("-", 60))
-	fmt.Println("\U0001F3F7\ufe0f  STANDAARDPRODUCTEN PER CATEGORIE:\n")
-
-	emojis := map[string]string{
-		"melk": "\U0001F95B", "boter": "\U0001F9C8", "brood": "\U0001F35E", "kaas": "\U0001F9C0", "eieren": "\U0001F95A",
-		"yoghurt": "\U0001F944", "vleeswaren": "\U0001F953", "fruit": "\U0001F34E", "groente": "\U0001F955",
-		"vlees": "\U0001F356", "drank": "\U0001F964", "snoep": "\U0001F36B", "diepvries": "\U0001F9CA",
-	}
+	fmt.Printf("%s\n\n", msg("analyze.defaults"))
 
 	for cat, prefs := range preferences {
-		emoji := emojis[cat]
-		if emoji == "" {
-			emoji = "\U0001F4E6"
-		}
-		fmt.Printf("%s %s\n", emoji, strings.ToUpper(cat))
+		fmt.Printf("%s %s\n", rules.emoji(cat), strings.ToUpper(rules.display(cat)))
 		fmt.Printf("   -> %s\n", prefs.Default.Name)
-		fmt.Printf("     ID: %s | %dx gekauft\n", prefs.Default.ID, prefs.Default.Count)
+		fmt.Printf("     %s\n", msg("analyze.bought", prefs.Default.ID, prefs.Default.Count))
 		if len(prefs.Alternatives) > 0 {
 			alts := make([]string, 0, len(prefs.Alternatives))
 			for i, alt := range prefs.Alternatives {
@@ -292,17 +250,37 @@ func showAnalysis(categories map[string][]productCount, preferences map[string]c
 				}
 				alts = append(alts, alt.Name)
 			}
-			fmt.Printf("     Alternativen: %s\n", strings.Join(alts, ", "))
+			fmt.Printf("     %s\n", msg("analyze.alternatives", strings.Join(alts, ", ")))
 		}
 		fmt.Println()
 	}
 
 	fmt.Println(strings.Repeat("=", 60))
-	fmt.Println("\u2705 Nu weet ik wat je bedoelt met \"koop melk\" enz.!")
+	fmt.Println(msg("analyze.done"))
 	fmt.Println(strings.Repeat("=", 60))
 
 }
 
+func loadHistory() ([]productEntry, error) {
+	path, err := historyFilePath()
+	if err != nil {
+		return nil, err
+	}
+	var products []productEntry
+	if err := readJSONFile(path, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func readJSONFile(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
 func writeJSONFile(path string, value interface{}) error {
 	data, err := json.MarshalIndent(value, "", "  ")
 	if err != nil {
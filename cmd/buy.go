@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Sources of a product found by name.
+const (
+	matchPreference = "preference"
+	matchSearch     = "search"
+)
+
+// productMatch is the product chosen for a name such as "milk".
+type productMatch struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price int    `json:"price_cents"`
+	// PriceMissing is set when search listed the product without a price.
+	PriceMissing bool   `json:"price_missing,omitempty"`
+	Unit         string `json:"unit,omitempty"`
+	Source       string `json:"source"`
+}
+
+func buyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "buy <name> [count]",
+		Short: "Add the usual product for a name, e.g. \"melk\", to the cart",
+		Long: "Adds the product you usually buy for the name, learned by analyze-orders,\n" +
+			"or the first search result when there is none.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			count := 1
+			if len(args) > 1 {
+				parsed, err := strconv.Atoi(args[1])
+				if err != nil || parsed < 1 {
+					return fmt.Errorf("invalid count: %s", args[1])
+				}
+				count = parsed
+			}
+			if err := noteFollowUp(); err != nil {
+				return err
+			}
+			match, err := findProductByName(args[0], searchArticlesRaw)
+			if err != nil {
+				if isAuthError(err) {
+					invalidateAuthCache()
+				}
+				return err
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			cart, err := client.AddToCart(match.ID, count)
+			if err != nil {
+				if isAuthError(err) {
+					invalidateAuthCache()
+				}
+				return err
+			}
+			fmt.Println(msg("buy.added", count, match.Name, match.ID, msg("buy.source."+match.Source)))
+			showCartSummary(cart)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// findProductByName prefers the product learned for the name by
+// analyze-orders: the most bought preferred product whose name matches it.
+// Without one it takes the first search result.
+func findProductByName(name string, search func(string) ([]searchResult, error)) (productMatch, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return productMatch{}, fmt.Errorf("name is required")
+	}
+	if match, ok := preferredProduct(name); ok {
+		return match, nil
+	}
+
+	results, err := search(name)
+	if err != nil {
+		return productMatch{}, err
+	}
+	recordSearchPrices(results)
+	for _, article := range results {
+		if article.Id != "" {
+			return productMatch{ID: article.Id, Name: article.Name, Price: article.PriceIncludingPromotions(), PriceMissing: article.PriceMissing, Unit: strings.TrimSpace(article.UnitQuantity), Source: matchSearch}, nil
+		}
+	}
+	return productMatch{}, fmt.Errorf("no product found for %q", name)
+}
+
+func preferredProduct(name string) (productMatch, bool) {
+	path, err := preferencesFilePath()
+	if err != nil {
+		return productMatch{}, false
+	}
+	preferences := map[string]categoryPreference{}
+	if err := readJSONFile(path, &preferences); err != nil {
+		return productMatch{}, false
+	}
+	// Only a product whose own name matches counts: the default of the
+	// category a name falls in is often another product, e.g. cucumber for
+	// wortel.
+	var best productCount
+	for _, pref := range preferences {
+		for _, p := range append([]productCount{pref.Default}, pref.Alternatives...) {
+			if p.ID != "" && nameMatches(p.Name, name) && p.Count > best.Count {
+				best = p
+			}
+		}
+	}
+	if best.ID != "" {
+		return productMatch{ID: best.ID, Name: best.Name, Price: best.Price, Unit: strings.TrimSpace(best.Unit), Source: matchPreference}, true
+	}
+	return productMatch{}, false
+}
+
+// nameMatches reports whether every word of query starts or ends a word of
+// product name, so that "melk" matches "Halfvolle melk" and "Karnemelk" but
+// "ei" does not match "Kleine".
+func nameMatches(product, query string) bool {
+	words := nameWords(product)
+	queryWords := nameWords(query)
+	if len(queryWords) == 0 {
+		return false
+	}
+	for _, q := range queryWords {
+		if !slices.ContainsFunc(words, func(w string) bool {
+			return strings.HasPrefix(w, q) || strings.HasSuffix(w, q)
+		}) {
+			return false
+		}
+	}
+	return true
+}
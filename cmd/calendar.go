@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// calendarEvent is one VEVENT of the exported calendar. Alarm is optional.
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Alarm       time.Time
+}
+
+func calendarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "calendar",
+		Short: "Delivery calendar",
+	}
+	cmd.AddCommand(calendarExportCmd())
+	return cmd
+}
+
+func calendarExportCmd() *cobra.Command {
+	var output string
+	var serve string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the selected slot and upcoming deliveries as iCalendar",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if serve != "" {
+				return serveCalendar(serve)
+			}
+			ics, err := buildCalendar()
+			if err != nil {
+				return err
+			}
+			if output == "-" {
+				fmt.Print(ics)
+				return nil
+			}
+			if err := os.WriteFile(output, []byte(ics), 0o644); err != nil {
+				return err
+			}
+			fmt.Println(msg("calendar.written", output))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "picnic.ics", `File to write, "-" for stdout`)
+	cmd.Flags().StringVar(&serve, "serve", "", "Serve the feed over HTTP on this address (e.g. 127.0.0.1:8765) instead of writing a file")
+	return cmd
+}
+
+// serveCalendar serves a freshly built feed on every request so calendar
+// subscriptions pick up changes.
+func serveCalendar(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/picnic.ics", func(w http.ResponseWriter, r *http.Request) {
+		ics, err := buildCalendar()
+		if err != nil {
+			invalidateAuthCache()
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_, _ = w.Write([]byte(ics))
+	})
+	fmt.Println(msg("calendar.serving", "http://"+addr+"/picnic.ics"))
+	return http.ListenAndServe(addr, mux)
+}
+
+func buildCalendar() (string, error) {
+	client, err := getClient()
+	if err != nil {
+		return "", err
+	}
+	events, err := calendarEvents(client)
+	if err != nil {
+		return "", err
+	}
+	return renderCalendar(events, time.Now()), nil
+}
+
+// calendarEvents collects the current deliveries and the selected slot of the
+// cart. A delivery uses its ETA when known, else the delivery or slot window.
+func calendarEvents(client interface {
+	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
+	GetDeliverySlots() (*picnic.DeliverySlots, error)
+}) ([]calendarEvent, error) {
+	var events []calendarEvent
+
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{picnic.CURRENT})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range *deliveries {
+		id := deliveryID(d)
+		start, end, ok := firstWindow(d.Eta2, d.DeliveryTime, picnic.DeliveryTime{Start: d.Slot.WindowStart, End: d.Slot.WindowEnd})
+		if !ok {
+			continue
+		}
+		description := []string{msg("calendar.window", d.Slot.WindowStart, d.Slot.WindowEnd)}
+		if d.Eta2.Start != "" {
+			description = append(description, msg("calendar.eta", d.Eta2.Start, d.Eta2.End))
+		}
+		description = append(description, "ID: "+id)
+		event := calendarEvent{
+			UID:         "delivery-" + id + "@picnic-cli",
+			Summary:     msg("calendar.delivery"),
+			Description: strings.Join(description, "\n"),
+			Start:       start,
+			End:         end,
+		}
+		if cutOff, ok := parseTimestamp(d.Slot.CutOffTime); ok {
+			event.Alarm = cutOff
+		}
+		events = append(events, event)
+	}
+
+	slots, err := client.GetDeliverySlots()
+	if err != nil {
+		return nil, err
+	}
+	if slot, ok := selectedSlot(slots); ok {
+		start, end, ok := firstWindow(picnic.DeliveryTime{Start: slot.WindowStart, End: slot.WindowEnd})
+		if ok {
+			event := calendarEvent{
+				UID:         "slot-" + slot.SlotId + "@picnic-cli",
+				Summary:     msg("calendar.selectedSlot"),
+				Description: msg("calendar.window", slot.WindowStart, slot.WindowEnd) + "\nID: " + slot.SlotId,
+				Start:       start,
+				End:         end,
+			}
+			if cutOff, ok := parseTimestamp(slot.CutOffTime); ok {
+				event.Alarm = cutOff
+				event.Description += "\n" + msg("calendar.cutOff", slot.CutOffTime)
+			}
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// firstWindow returns the first window with a parseable start.
+func firstWindow(windows ...picnic.DeliveryTime) (time.Time, time.Time, bool) {
+	for _, w := range windows {
+		start, ok := parseTimestamp(w.Start)
+		if !ok {
+			continue
+		}
+		end, ok := parseTimestamp(w.End)
+		if !ok || end.Before(start) {
+			end = start.Add(time.Hour)
+		}
+		return start, end, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// renderCalendar writes the events as an RFC 5545 calendar.
+func renderCalendar(events []calendarEvent, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//picnic-cli//calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:Picnic")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + icsTime(now))
+		line("DTSTART:" + icsTime(e.Start))
+		line("DTEND:" + icsTime(e.End))
+		line("SUMMARY:" + icsEscape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION:" + icsEscape(e.Description))
+		}
+		if !e.Alarm.IsZero() {
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("TRIGGER;VALUE=DATE-TIME:" + icsTime(e.Alarm))
+			line("DESCRIPTION:" + icsEscape(msg("calendar.cutOffAlarm")))
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets without breaking
+// UTF-8 sequences.
+func foldICSLine(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74
+	}
+	b.WriteString(s)
+	return b.String()
+}
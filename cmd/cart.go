@@ -21,10 +21,12 @@ func cartCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
+			recordCartPrices(cart)
 			showCart(cart)
 			return nil
 		},
 	}
+	cmd.AddCommand(cartCheckCmd())
 	return cmd
 }
 
@@ -33,17 +35,23 @@ func showCartSummary(cart *picnic.Order) {
 		return
 	}
 	items := cart.TotalCount
-	total := formatPrice(cart.TotalPrice)
-	fmt.Printf("\n\U0001F6D2 Cart: %d items | Total: %s\n", items, total)
+	total := amount(cart.TotalPrice)
+	fmt.Printf("\n%s\n", msg("cart.summary", items, total))
+	if cart.TotalSavings > 0 {
+		fmt.Println(msg("cart.savings", amount(cart.TotalSavings).Neg()))
+	}
+	if cart.TotalDeposit != 0 {
+		fmt.Println(msg("cart.deposit", amount(cart.TotalDeposit)))
+	}
 }
 
 func showCart(cart *picnic.Order) {
 	if cart == nil || len(cart.Items) == 0 {
-		fmt.Println("\U0001F6D2 Cart is empty")
+		fmt.Println(msg("cart.empty"))
 		return
 	}
 
-	fmt.Println("\U0001F6D2 Shopping Cart:\n")
+	fmt.Printf("%s\n\n", msg("cart.header"))
 	for _, line := range cart.Items {
 		if len(line.Items) == 0 {
 			continue
@@ -56,7 +64,7 @@ func showCart(cart *picnic.Order) {
 			if qty == 0 {
 				qty = 1
 			}
-			price := formatPrice(article.DisplayPrice)
+			price := amount(article.DisplayPrice)
 			fmt.Printf("  %dx %s %s\n", qty, article.Name, price)
 		}
 	}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"sort"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	fillMaxCandidates = 30
+	fillMaxPerProduct = 2
+)
+
+// fillItem is a product proposed to reach the minimum order value.
+type fillItem struct {
+	ID        string
+	Name      string
+	Price     int
+	Count     int
+	Purchases int
+}
+
+func cartCheckCmd() *cobra.Command {
+	var fill bool
+	var yes bool
+	var minCount int
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Compare the cart total to the selected slot's minimum order value",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			cart, err := client.GetCart()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: cart.DeliverySlots, SelectedSlot: cart.SelectedSlot})
+			if !ok {
+				slots, err := client.GetDeliverySlots()
+				if err != nil {
+					invalidateAuthCache()
+					return err
+				}
+				slot, ok = selectedSlot(slots)
+			}
+			if !ok {
+				fmt.Println(msg("check.noSlot"))
+				return nil
+			}
+
+			window := slot.WindowStart + " - " + slot.WindowEnd
+			gap := slot.MinimumOrderValue - cart.TotalPrice
+			if gap <= 0 {
+				fmt.Println(msg("check.ok", amount(cart.TotalPrice), amount(slot.MinimumOrderValue), window))
+				return nil
+			}
+			fmt.Println(msg("check.below", amount(cart.TotalPrice), amount(slot.MinimumOrderValue), window, amount(gap)))
+			if !fill {
+				return nil
+			}
+
+			rules, err := loadCategoryRules("", currentCountry())
+			if err != nil {
+				return err
+			}
+			candidates, err := fillCandidates(rules, minCount, cartProductIDs(cart))
+			if err != nil {
+				return err
+			}
+			proposal := proposeFill(candidates, gap)
+			if len(proposal) == 0 {
+				fmt.Println(msg("check.noProposal"))
+				return nil
+			}
+
+			total := 0
+			fmt.Printf("\n%s\n\n", msg("check.proposal"))
+			for _, item := range proposal {
+				total += item.Price * item.Count
+				fmt.Printf("  %dx %s %s [%s]\n", item.Count, item.Name, amount(item.Price), item.ID)
+			}
+			fmt.Printf("\n%s\n", msg("check.proposalTotal", amount(total), amount(total-gap)))
+
+			if !yes && !confirm(msg("check.confirm", len(proposal))) {
+				return nil
+			}
+			var last *picnic.Order
+			for _, item := range proposal {
+				order, err := client.AddToCart(item.ID, item.Count)
+				if err != nil {
+					invalidateAuthCache()
+					return err
+				}
+				fmt.Println(msg("cart.added", item.Count, item.ID))
+				last = order
+			}
+			showCartSummary(last)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&fill, "fill", false, "Propose products from your history to reach the minimum")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Add the proposed products without asking")
+	cmd.Flags().IntVar(&minCount, "min-count", 2, "Minimum number of deliveries with a product for it to be proposed")
+	return cmd
+}
+
+// fillCandidates returns non-perishable products bought in at least minCount
+// deliveries of the local history with their latest known unit price.
+func fillCandidates(rules *categoryRules, minCount int, inCart map[string]bool) ([]fillItem, error) {
+	history, err := loadHistory()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	observations, err := loadPriceObservations()
+	if err != nil {
+		return nil, err
+	}
+	latest := map[string]int{}
+	for _, obs := range observations {
+		latest[obs.ID] = obs.Price
+	}
+
+	deliveries := map[string]map[string]bool{}
+	names := map[string]string{}
+	for _, p := range history {
+		if deliveries[p.ID] == nil {
+			deliveries[p.ID] = map[string]bool{}
+		}
+		deliveries[p.ID][p.Date] = true
+		names[p.ID] = p.Name
+	}
+
+	var candidates []fillItem
+	for id, dates := range deliveries {
+		count := len(dates)
+		if count < minCount || inCart[id] || latest[id] <= 0 || rules.perishable(names[id]) {
+			continue
+		}
+		candidates = append(candidates, fillItem{ID: id, Name: names[id], Price: latest[id], Purchases: count})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Purchases == candidates[j].Purchases {
+			return candidates[i].Name < candidates[j].Name
+		}
+		return candidates[i].Purchases > candidates[j].Purchases
+	})
+	if len(candidates) > fillMaxCandidates {
+		candidates = candidates[:fillMaxCandidates]
+	}
+	return candidates, nil
+}
+
+// proposeFill picks units of the candidates whose sum reaches gap with the
+// smallest overshoot, preferring fewer units. It is a 0/1 knapsack over up to
+// fillMaxPerProduct units of every candidate.
+func proposeFill(candidates []fillItem, gap int) []fillItem {
+	if gap <= 0 || len(candidates) == 0 {
+		return nil
+	}
+	var units []fillItem
+	maxPrice := 0
+	for _, c := range candidates {
+		for i := 0; i < fillMaxPerProduct; i++ {
+			units = append(units, fillItem{ID: c.ID, Name: c.Name, Price: c.Price, Count: 1})
+		}
+		if c.Price > maxPrice {
+			maxPrice = c.Price
+		}
+	}
+
+	limit := gap + maxPrice
+	best := make([]int, limit+1)
+	for s := range best {
+		best[s] = math.MaxInt32
+	}
+	best[0] = 0
+	keep := make([][]bool, len(units))
+	for i, u := range units {
+		keep[i] = make([]bool, limit+1)
+		for s := limit; s >= u.Price; s-- {
+			if best[s-u.Price] != math.MaxInt32 && best[s-u.Price]+1 < best[s] {
+				best[s] = best[s-u.Price] + 1
+				keep[i][s] = true
+			}
+		}
+	}
+
+	target := -1
+	for s := gap; s <= limit; s++ {
+		if best[s] != math.MaxInt32 {
+			target = s
+			break
+		}
+	}
+	if target < 0 {
+		return nil
+	}
+
+	counts := map[string]*fillItem{}
+	var order []string
+	for i := len(units) - 1; i >= 0 && target > 0; i-- {
+		if !keep[i][target] {
+			continue
+		}
+		u := units[i]
+		target -= u.Price
+		if item, ok := counts[u.ID]; ok {
+			item.Count++
+			continue
+		}
+		item := u
+		counts[u.ID] = &item
+		order = append(order, u.ID)
+	}
+	proposal := make([]fillItem, 0, len(order))
+	for i := len(order) - 1; i >= 0; i-- {
+		proposal = append(proposal, *counts[order[i]])
+	}
+	return proposal
+}
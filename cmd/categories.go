@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+const catalogCacheTTL = 7 * 24 * time.Hour
+
+// catalogCache is the flattened Picnic catalog: article ID to the name of the
+// catalog category it is listed under.
+type catalogCache struct {
+	Timestamp int64             `json:"timestamp"`
+	Articles  map[string]string `json:"articles"`
+}
+
+// productClassifier assigns a category to a product. User overrides win over
+// the Picnic catalog, which wins over the category rules.
+type productClassifier struct {
+	overrides map[string]string
+	catalog   map[string]string
+	rules     *categoryRules
+}
+
+func (c *productClassifier) classify(id, name string) string {
+	if cat := c.overrides[id]; cat != "" {
+		return cat
+	}
+	if cat := c.catalog[id]; cat != "" {
+		return cat
+	}
+	return c.rules.match(name)
+}
+
+// loadProductClassifier builds a classifier from the user override file and
+// the cached catalog, refreshing the cache from GetMyStore when it is missing,
+// stale or refresh is set. Catalog failures fall back to the category rules.
+func loadProductClassifier(client interface {
+	GetMyStore() (*picnic.MyStore, error)
+}, refresh bool, rules *categoryRules) *productClassifier {
+	classifier := &productClassifier{
+		overrides: map[string]string{},
+		catalog:   map[string]string{},
+		rules:     rules,
+	}
+
+	if path, err := categoryOverridesFilePath(); err == nil {
+		if err := readJSONFile(path, &classifier.overrides); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, msg("analyze.ignoringFile", path, err))
+		}
+	}
+
+	cache, err := loadCatalogCache(client, refresh)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("analyze.catalogUnavailable", err))
+		return classifier
+	}
+	classifier.catalog = cache.Articles
+	return classifier
+}
+
+func loadCatalogCache(client interface {
+	GetMyStore() (*picnic.MyStore, error)
+}, refresh bool) (catalogCache, error) {
+	path, err := catalogFilePath()
+	if err != nil {
+		return catalogCache{}, err
+	}
+
+	var cache catalogCache
+	if !refresh {
+		if err := readJSONFile(path, &cache); err == nil && len(cache.Articles) > 0 &&
+			time.Since(time.UnixMilli(cache.Timestamp)) < catalogCacheTTL {
+			return cache, nil
+		}
+	}
+
+	store, err := client.GetMyStore()
+	if err != nil {
+		return catalogCache{}, err
+	}
+	cache = catalogCache{
+		Timestamp: time.Now().UnixMilli(),
+		Articles:  map[string]string{},
+	}
+	for _, category := range store.Catalog {
+		flattenCatalog(category, "", cache.Articles)
+	}
+	_ = writeJSONFile(path, cache)
+	return cache, nil
+}
+
+// flattenCatalog walks the category tree and records every article under the
+// nearest enclosing category.
+func flattenCatalog(node picnic.Category, parent string, out map[string]string) {
+	if node.Type == "SINGLE_ARTICLE" {
+		if node.Id != "" && parent != "" {
+			out[node.Id] = parent
+		}
+		return
+	}
+	name := parent
+	if node.Name != "" {
+		name = node.Name
+	}
+	for _, child := range node.Items {
+		flattenCatalog(child, name, out)
+	}
+}
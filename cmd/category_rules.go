@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+//go:embed rules/*.json
+var defaultRulesFS embed.FS
+
+const defaultCategoryEmoji = "\U0001F4E6"
+
+// categoryDefinition describes one category in a rules file. Patterns are
+// case-insensitive regular expressions matched against the product name,
+// keywords are matched against whole words of the name. Perishable marks
+// products that should not be bought ahead of need.
+type categoryDefinition struct {
+	Name       string   `json:"name"`
+	Display    string   `json:"display,omitempty"`
+	Emoji      string   `json:"emoji,omitempty"`
+	Patterns   []string `json:"patterns,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+	Perishable bool     `json:"perishable,omitempty"`
+}
+
+type categoryRulesFile struct {
+	Other      categoryDefinition   `json:"other"`
+	Categories []categoryDefinition `json:"categories"`
+}
+
+type categoryRule struct {
+	Name     string
+	Patterns []*regexp.Regexp
+	Keywords map[string]bool
+}
+
+func (r categoryRule) matches(name string) bool {
+	for _, pattern := range r.Patterns {
+		if pattern.MatchString(name) {
+			return true
+		}
+	}
+	if len(r.Keywords) == 0 {
+		return false
+	}
+	for _, word := range nameWords(name) {
+		if r.Keywords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryRules is a validated rules file. Rules are tried in file order.
+type categoryRules struct {
+	other       categoryDefinition
+	definitions map[string]categoryDefinition
+	rules       []categoryRule
+}
+
+func (r *categoryRules) match(name string) string {
+	for _, rule := range r.rules {
+		if rule.matches(name) {
+			return rule.Name
+		}
+	}
+	return r.other.Name
+}
+
+// display returns the display name of a category. Categories without a
+// definition, such as catalog categories, are shown as-is.
+func (r *categoryRules) display(cat string) string {
+	if def, ok := r.definitions[cat]; ok && def.Display != "" {
+		return def.Display
+	}
+	return cat
+}
+
+func (r *categoryRules) emoji(cat string) string {
+	if def, ok := r.definitions[cat]; ok && def.Emoji != "" {
+		return def.Emoji
+	}
+	return defaultCategoryEmoji
+}
+
+// perishable reports whether a product name falls in a perishable category.
+func (r *categoryRules) perishable(name string) bool {
+	return r.definitions[r.match(name)].Perishable
+}
+
+// loadCategoryRules reads the rules from path if given, then from the user
+// rules file, and otherwise uses the shipped defaults for the country.
+func loadCategoryRules(path, country string) (*categoryRules, error) {
+	if strings.TrimSpace(path) != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules: %w", err)
+		}
+		return parseCategoryRules(data, path)
+	}
+
+	if userPath, err := rulesFilePath(); err == nil {
+		data, err := os.ReadFile(userPath)
+		if err == nil {
+			return parseCategoryRules(data, userPath)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read rules: %w", err)
+		}
+	}
+
+	return defaultCategoryRules(country)
+}
+
+func defaultCategoryRules(country string) (*categoryRules, error) {
+	name := "rules/" + strings.ToLower(strings.TrimSpace(country)) + ".json"
+	data, err := defaultRulesFS.ReadFile(name)
+	if err != nil {
+		name = "rules/nl.json"
+		data, err = defaultRulesFS.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseCategoryRules(data, name)
+}
+
+// parseCategoryRules decodes and validates a rules file, reporting every
+// problem found rather than only the first.
+func parseCategoryRules(data []byte, source string) (*categoryRules, error) {
+	var file categoryRulesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid rules in %s: %w", source, err)
+	}
+
+	var problems []string
+	rules := &categoryRules{
+		other:       file.Other,
+		definitions: map[string]categoryDefinition{},
+	}
+	if strings.TrimSpace(file.Other.Name) == "" {
+		problems = append(problems, "other: name is required")
+	} else {
+		rules.definitions[file.Other.Name] = file.Other
+	}
+	if len(file.Categories) == 0 {
+		problems = append(problems, "no categories defined")
+	}
+
+	for i, def := range file.Categories {
+		label := fmt.Sprintf("categories[%d]", i)
+		if strings.TrimSpace(def.Name) == "" {
+			problems = append(problems, label+": name is required")
+			continue
+		}
+		label = fmt.Sprintf("%s (%s)", label, def.Name)
+		if _, dup := rules.definitions[def.Name]; dup {
+			problems = append(problems, label+": duplicate category name")
+			continue
+		}
+		if len(def.Patterns) == 0 && len(def.Keywords) == 0 {
+			problems = append(problems, label+": needs at least one pattern or keyword")
+		}
+
+		rule := categoryRule{Name: def.Name, Keywords: map[string]bool{}}
+		for _, pattern := range def.Patterns {
+			if strings.TrimSpace(pattern) == "" {
+				problems = append(problems, label+": empty pattern")
+				continue
+			}
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid pattern %q: %s", label, pattern, err))
+				continue
+			}
+			rule.Patterns = append(rule.Patterns, re)
+		}
+		for _, keyword := range def.Keywords {
+			words := nameWords(keyword)
+			if len(words) != 1 {
+				problems = append(problems, fmt.Sprintf("%s: keyword %q must be a single word", label, keyword))
+				continue
+			}
+			rule.Keywords[words[0]] = true
+		}
+
+		rules.definitions[def.Name] = def
+		rules.rules = append(rules.rules, rule)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid rules in %s:\n  %s", source, strings.Join(problems, "\n  "))
+	}
+	return rules, nil
+}
+
+// nameWords splits a product name into lower-case words, treating anything
+// but letters and digits as a separator.
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
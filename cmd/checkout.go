@@ -17,14 +17,20 @@ func checkoutCmd() *cobra.Command {
 	cmd.AddCommand(checkoutStatusCmd())
 	cmd.AddCommand(checkoutCancelCmd())
 	cmd.AddCommand(checkoutPayCmd())
+	cmd.AddCommand(checkoutRunCmd())
+	cmd.AddCommand(checkoutWaitCmd())
 	return cmd
 }
 
 func checkoutStartCmd() *cobra.Command {
-	var resolveKey string
+	var resolveKeys []string
+	var oosArticleIDs []string
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start checkout for the current cart",
+		Long: "Checks the cart against the configured guardrails and starts checkout.\n" +
+			"The monthly cap counts this month's current and completed deliveries as Picnic\n" +
+			"reports them, not the local order history.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
@@ -38,36 +44,80 @@ func checkoutStartCmd() *cobra.Command {
 			if cart.TotalCount == 0 {
 				return fmt.Errorf("cart is empty")
 			}
-
-			var checkout *picnic.Checkout
-			var cerr *picnic.CheckoutError
-			if strings.TrimSpace(resolveKey) != "" {
-				checkout, cerr = client.CheckoutWithResolveKey(cart.Mts, resolveKey)
-			} else {
-				checkout, cerr = client.StartCheckout(cart.Mts)
+			if err := enforceGuardrails(client, cart); err != nil {
+				return err
 			}
-			if cerr != nil {
-				fmt.Printf("Checkout error: %s\n", cerr.Error())
-				if cerr.Title != "" || cerr.Message != "" {
-					fmt.Printf("%s - %s\n", cerr.Title, cerr.Message)
-				}
-				if cerr.ResolveKey != "" {
-					fmt.Printf("Resolve key required: %s\n", cerr.ResolveKey)
-				}
-				if cerr.Blocking {
-					fmt.Println("Blocking: true")
-				}
+
+			checkout, issue := startCheckoutRaw(cart, trimmedValues(resolveKeys), trimmedValues(oosArticleIDs))
+			if issue != nil {
+				showCheckoutError(issue, cartArticleNames(cart))
 				return nil
 			}
-			fmt.Printf("Checkout started. Order ID: %s\n", checkout.OrderId)
-			fmt.Printf("Total: %s | Items: %d\n", formatPrice(checkout.TotalPrice), checkout.TotalCount)
+			showCheckout(checkout)
 			return nil
 		},
 	}
-	cmd.Flags().StringVar(&resolveKey, "resolve", "", "Resolve key if required (e.g., age_verified)")
+	cmd.Flags().StringSliceVar(&resolveKeys, "resolve", nil, "Resolve keys if required (e.g., age_verified), repeatable")
+	cmd.Flags().StringSliceVar(&oosArticleIDs, "accept-oos", nil, "Out-of-stock article IDs to check out without, repeatable")
 	return cmd
 }
 
+func showCheckoutError(issue *checkoutIssue, names map[string]string) {
+	fmt.Println(msg("checkout.error", issue.Error()))
+	if issue.Title != "" || issue.Message != "" {
+		fmt.Printf("%s - %s\n", issue.Title, issue.Message)
+	}
+	if issue.ResolveKey != "" {
+		fmt.Println(msg("checkout.resolveRequired", issue.ResolveKey))
+	}
+	if issue.Blocking {
+		fmt.Println(msg("checkout.blocking"))
+	}
+	if len(issue.OosArticleIds) > 0 {
+		fmt.Println(msg("checkout.outOfStock"))
+		for _, id := range issue.OosArticleIds {
+			if name := names[id]; name != "" {
+				fmt.Printf("  %s (%s)\n", name, id)
+			} else {
+				fmt.Printf("  %s\n", id)
+			}
+		}
+		fmt.Println(msg("checkout.acceptOOSHint", strings.Join(issue.OosArticleIds, ",")))
+	}
+}
+
+// cartArticleNames maps the article ids in the cart to their names.
+func cartArticleNames(cart *picnic.Order) map[string]string {
+	names := map[string]string{}
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			names[article.Id] = article.Name
+		}
+	}
+	return names
+}
+
+func trimmedValues(values []string) []string {
+	var out []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
+
+func showCheckout(checkout *picnic.Checkout) {
+	fmt.Println(msg("checkout.started", checkout.OrderId))
+	fmt.Println(msg("checkout.total", amount(checkout.TotalPrice), checkout.TotalCount))
+	if checkout.TotalSavings > 0 {
+		fmt.Println(msg("cart.savings", amount(checkout.TotalSavings).Neg()))
+	}
+	for _, deposit := range checkout.DepositBreakdown {
+		fmt.Println(msg("checkout.depositLine", deposit.Count, deposit.Type, amount(deposit.Value)))
+	}
+}
+
 func checkoutStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "status <transaction_id>",
@@ -83,7 +133,7 @@ func checkoutStatusCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Printf("Checkout status: %s\n", status)
+			fmt.Println(msg("checkout.status", status))
 			return nil
 		},
 	}
@@ -104,7 +154,7 @@ func checkoutCancelCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Println("Checkout cancelled")
+			fmt.Println(msg("checkout.cancelled"))
 			return nil
 		},
 	}
@@ -112,29 +162,35 @@ func checkoutCancelCmd() *cobra.Command {
 }
 
 func checkoutPayCmd() *cobra.Command {
+	var expectMethod string
+	var asJSON bool
 	cmd := &cobra.Command{
 		Use:   "pay <order_id>",
 		Short: "Initiate payment for an order",
-		Args:  cobra.ExactArgs(1),
+		Long: "Checks the placed order against the configured guardrails, like 'checkout start',\n" +
+			"then initiates payment with the preferred payment option.",
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := getClient()
 			if err != nil {
 				return err
 			}
-			payment, err := client.InitiatePayment(args[0])
-			if err != nil {
-				invalidateAuthCache()
+			if err := enforceOrderGuardrails(client, args[0]); err != nil {
 				return err
 			}
-			fmt.Printf("Payment initiated. Transaction ID: %s\n", payment.TransactionId)
-			if payment.IssuerAuthenticationUrl != "" {
-				fmt.Printf("Issuer authentication URL: %s\n", payment.IssuerAuthenticationUrl)
+			choice, err := resolvePaymentChoice(client, expectMethod)
+			if err != nil {
+				return err
 			}
-			if payment.Action.RedirectUrl != "" {
-				fmt.Printf("Redirect URL: %s\n", payment.Action.RedirectUrl)
+			result, err := initiatePayment(client, args[0], choice)
+			if err != nil {
+				invalidateAuthCache()
+				return err
 			}
-			return nil
+			return showPaymentResult(result, asJSON)
 		},
 	}
+	addPaymentFlags(cmd, &expectMethod)
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the result as JSON")
 	return cmd
 }
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+// checkoutIssue is a checkout error together with the out-of-stock articles
+// in the cart, which can be acknowledged to check out without them.
+type checkoutIssue struct {
+	*picnic.CheckoutError
+	OosArticleIds []string
+}
+
+// startCheckoutRaw starts checkout for the cart, acknowledging the given
+// out-of-stock article ids. The library always sends "age_verified" as
+// resolve key, so checkout is started with raw picnic.CheckoutStart requests
+// instead. The API takes one resolve_key per request, so each key is sent in
+// a request of its own, in order, as long as the next issue asks for one of
+// the keys still to send.
+func startCheckoutRaw(cart *picnic.Order, resolveKeys, oosArticleIDs []string) (*picnic.Checkout, *checkoutIssue) {
+	keys := resolveKeys
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+	if oosArticleIDs == nil {
+		oosArticleIDs = []string{}
+	}
+	var issue *checkoutIssue
+	for i, key := range keys {
+		var checkout *picnic.Checkout
+		checkout, issue = postCheckoutStart(picnic.CheckoutStart{
+			Mts:           cart.Mts,
+			OosArticleIds: oosArticleIDs,
+			ResolveKey:    key,
+		})
+		if issue == nil {
+			return checkout, nil
+		}
+		if issue.Code == "" || issue.ResolveKey == "" || !slices.Contains(keys[i+1:], issue.ResolveKey) {
+			break
+		}
+	}
+	if issue.Code != "" {
+		issue.OosArticleIds = unavailableArticleIDs(cart, oosArticleIDs)
+	}
+	return nil, issue
+}
+
+func postCheckoutStart(request picnic.CheckoutStart) (*picnic.Checkout, *checkoutIssue) {
+	req, err := newRawRequest("POST", "/cart/checkout/start", request)
+	if err != nil {
+		return nil, wrapCheckoutIssue(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, wrapCheckoutIssue(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, parseCheckoutIssue(resp)
+	}
+	var checkout picnic.Checkout
+	if err := json.NewDecoder(resp.Body).Decode(&checkout); err != nil {
+		return nil, wrapCheckoutIssue(err)
+	}
+	return &checkout, nil
+}
+
+// unavailableArticleIDs lists the articles the cart marks unavailable that
+// were not acknowledged yet.
+func unavailableArticleIDs(cart *picnic.Order, acknowledged []string) []string {
+	var ids []string
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id != "" && !article.IsAvailable() && !slices.Contains(acknowledged, article.Id) && !slices.Contains(ids, article.Id) {
+				ids = append(ids, article.Id)
+			}
+		}
+	}
+	return ids
+}
+
+func parseCheckoutIssue(resp *http.Response) *checkoutIssue {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return wrapCheckoutIssue(err)
+	}
+	var payload struct {
+		Error struct {
+			Code    string                    `json:"code"`
+			Message string                    `json:"message"`
+			Details picnic.PicnicErrorDetails `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error.Code == "" {
+		return wrapCheckoutIssue(fmt.Errorf("checkout failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+	details := payload.Error.Details
+	return &checkoutIssue{
+		CheckoutError: &picnic.CheckoutError{
+			Code:       details.Type,
+			Title:      details.LocalizedTitle,
+			Message:    details.LocalizedMessage,
+			ResolveKey: details.ResolveKey,
+			Blocking:   details.Blocking,
+			Err:        fmt.Errorf("checkout failed with code %s: %s", payload.Error.Code, payload.Error.Message),
+		},
+	}
+}
+
+func wrapCheckoutIssue(err error) *checkoutIssue {
+	return &checkoutIssue{CheckoutError: &picnic.CheckoutError{Err: err}}
+}
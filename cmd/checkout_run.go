@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// maxResolveRounds bounds how often checkout is retried after resolving issues.
+const maxResolveRounds = 5
+
+func checkoutRunCmd() *cobra.Command {
+	var yes bool
+	var resolveKeys []string
+	var oosArticleIDs []string
+	var expectMethod string
+	var interval time.Duration
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Check the cart and slot, check out, pay and wait for the result",
+		Long: "Runs the whole checkout: validates the cart and selected slot, starts checkout,\n" +
+			"resolves checkout issues, initiates payment and polls the status until it is final.\n" +
+			"Exits like 'checkout wait' once the payment is initiated.\n" +
+			"With --yes no questions are asked; only resolve keys and out-of-stock articles\n" +
+			"given with --resolve and --accept-oos are accepted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			cart, err := client.GetCart()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			if err := validateCheckoutCart(cart); err != nil {
+				return err
+			}
+			if err := enforceGuardrails(client, cart); err != nil {
+				return err
+			}
+			choice, err := resolvePaymentChoice(client, expectMethod)
+			if err != nil {
+				return err
+			}
+			if !yes && !confirm(msg("checkout.confirm", amount(cart.TotalPrice))) {
+				return fmt.Errorf("checkout aborted")
+			}
+
+			return placeOrder(client, cart, checkoutOptions{
+				yes:           yes,
+				resolveKeys:   resolveKeys,
+				oosArticleIDs: oosArticleIDs,
+				payment:       choice,
+				interval:      interval,
+				timeout:       timeout,
+			})
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().StringSliceVar(&resolveKeys, "resolve", nil, "Resolve keys to accept without asking (e.g., age_verified)")
+	cmd.Flags().StringSliceVar(&oosArticleIDs, "accept-oos", nil, "Out-of-stock article IDs to drop from the order without asking")
+	addPaymentFlags(cmd, &expectMethod)
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Time between status polls")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Minute, "Give up waiting for the payment after this long")
+	return cmd
+}
+
+// checkoutOptions are the choices made for placeOrder.
+type checkoutOptions struct {
+	yes           bool
+	resolveKeys   []string
+	oosArticleIDs []string
+	payment       paymentChoice
+	interval      time.Duration
+	timeout       time.Duration
+}
+
+// placeOrder checks out the cart, initiates payment and waits for the outcome.
+func placeOrder(client *picnic.Client, cart *picnic.Order, opts checkoutOptions) error {
+	checkout, err := runCheckout(cart, opts.resolveKeys, opts.oosArticleIDs, opts.yes)
+	if err != nil {
+		return err
+	}
+	showCheckout(checkout)
+
+	payment, err := initiatePayment(client, checkout.OrderId, opts.payment)
+	if err != nil {
+		invalidateAuthCache()
+		return err
+	}
+	if err := showPaymentResult(payment, false); err != nil {
+		return err
+	}
+
+	status, err := waitForCheckout(client, payment.TransactionID, backoff{interval: opts.interval, max: 30 * time.Second}, opts.timeout, false)
+	if err != nil {
+		return err
+	}
+	outcome, _ := checkoutOutcomeFor(status)
+	fmt.Println(msg("checkout.outcome", outcome, status))
+	if outcome == outcomeSuccess {
+		showPlacedOrder(client, checkout.OrderId)
+	}
+	return outcomeError(outcome, status)
+}
+
+// validateCheckoutCart shows the cart and selected slot and rejects carts that
+// cannot be checked out.
+func validateCheckoutCart(cart *picnic.Order) error {
+	if cart.TotalCount == 0 {
+		return fmt.Errorf("cart is empty")
+	}
+	slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: cart.DeliverySlots, SelectedSlot: cart.SelectedSlot})
+	if !ok {
+		return fmt.Errorf("no delivery slot selected, use 'picnic slot set' or 'picnic slot pick'")
+	}
+	showCart(cart)
+	fmt.Println()
+	showSlot(slot)
+	if !slot.IsAvailable {
+		return fmt.Errorf("selected slot %s is no longer available", slot.SlotId)
+	}
+	if gap := slot.MinimumOrderValue - cart.TotalPrice; gap > 0 {
+		return fmt.Errorf("cart is %s below the slot minimum of %s, see 'picnic cart check --fill'", amount(gap), amount(slot.MinimumOrderValue))
+	}
+	return nil
+}
+
+// runCheckout starts checkout and retries after each issue that can be
+// resolved: non-blocking issues with a resolve key and out-of-stock articles.
+// Keys and articles given up front are accepted; others are asked for unless
+// yes is set, in which case checkout stops.
+func runCheckout(cart *picnic.Order, acceptedKeys, acceptedOOS []string, yes bool) (*picnic.Checkout, error) {
+	allowedKeys := map[string]bool{}
+	for _, key := range trimmedValues(acceptedKeys) {
+		allowedKeys[key] = true
+	}
+	allowedOOS := map[string]bool{}
+	for _, id := range trimmedValues(acceptedOOS) {
+		allowedOOS[id] = true
+	}
+	names := cartArticleNames(cart)
+
+	var resolveKeys, oosArticleIDs []string
+	resolved := map[string]bool{}
+	for round := 0; round < maxResolveRounds; round++ {
+		checkout, issue := startCheckoutRaw(cart, resolveKeys, oosArticleIDs)
+		if issue == nil {
+			return checkout, nil
+		}
+		showCheckoutError(issue, names)
+
+		progress := false
+		if len(issue.OosArticleIds) > 0 {
+			var missing []string
+			for _, id := range issue.OosArticleIds {
+				if !resolved["oos:"+id] {
+					missing = append(missing, id)
+				}
+			}
+			needsApproval := false
+			for _, id := range missing {
+				if !allowedOOS[id] {
+					needsApproval = true
+				}
+			}
+			if needsApproval {
+				if yes {
+					return nil, fmt.Errorf("articles out of stock, pass --accept-oos %s to check out without them", strings.Join(missing, ","))
+				}
+				if !confirm(msg("checkout.oosPrompt", len(missing))) {
+					return nil, fmt.Errorf("checkout aborted")
+				}
+			}
+			for _, id := range missing {
+				resolved["oos:"+id] = true
+				oosArticleIDs = append(oosArticleIDs, id)
+				progress = true
+			}
+		}
+
+		if key := issue.ResolveKey; key != "" && !resolved[key] {
+			if issue.Blocking {
+				return nil, fmt.Errorf("checkout issue %s cannot be resolved", key)
+			}
+			if !allowedKeys[key] {
+				if yes {
+					return nil, fmt.Errorf("checkout needs resolve key %s, pass --resolve %s to accept it", key, key)
+				}
+				if !confirm(msg("checkout.resolvePrompt", key)) {
+					return nil, fmt.Errorf("checkout aborted")
+				}
+			}
+			resolved[key] = true
+			resolveKeys = append(resolveKeys, key)
+			progress = true
+		}
+
+		if !progress {
+			if issue.Code == "" {
+				invalidateAuthCache()
+			}
+			return nil, issue
+		}
+	}
+	return nil, fmt.Errorf("checkout still has issues after %d attempts", maxResolveRounds)
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// checkoutOutcome is what a raw checkout status means for waiting.
+type checkoutOutcome string
+
+const (
+	outcomePending   checkoutOutcome = "pending"
+	outcomeSuccess   checkoutOutcome = "success"
+	outcomeFailed    checkoutOutcome = "failed"
+	outcomeCancelled checkoutOutcome = "cancelled"
+	outcomeExpired   checkoutOutcome = "expired"
+)
+
+// Exit codes of checkout wait and checkout run for outcomes other than success.
+const (
+	exitCheckoutFailed    = 20
+	exitCheckoutCancelled = 21
+	exitCheckoutExpired   = 22
+	exitCheckoutTimeout   = 23
+)
+
+// checkoutStatuses maps the raw statuses to their outcome. Picnic does not
+// document them; picnic-api passes on the checkout_status field of
+// /cart/checkout/<id>/status as is. The names are those of the usual payment
+// providers, so statuses not listed here are reported by waitForCheckout.
+var checkoutStatuses = map[string]checkoutOutcome{
+	"":            outcomePending,
+	"PENDING":     outcomePending,
+	"IN_PROGRESS": outcomePending,
+	"PROCESSING":  outcomePending,
+	"OPEN":        outcomePending,
+	"CREATED":     outcomePending,
+	"INITIATED":   outcomePending,
+	"SUCCESS":     outcomeSuccess,
+	"SUCCEEDED":   outcomeSuccess,
+	"COMPLETED":   outcomeSuccess,
+	"PAID":        outcomeSuccess,
+	"AUTHORISED":  outcomeSuccess,
+	"AUTHORIZED":  outcomeSuccess,
+	"FAILED":      outcomeFailed,
+	"FAILURE":     outcomeFailed,
+	"REJECTED":    outcomeFailed,
+	"REFUSED":     outcomeFailed,
+	"ERROR":       outcomeFailed,
+	"CANCELLED":   outcomeCancelled,
+	"CANCELED":    outcomeCancelled,
+	"EXPIRED":     outcomeExpired,
+}
+
+// checkoutOutcomeFor maps a raw status to an outcome. Unknown statuses are
+// treated as pending so that waiting continues until the timeout; ok is
+// false for them.
+func checkoutOutcomeFor(status string) (outcome checkoutOutcome, ok bool) {
+	outcome, ok = checkoutStatuses[strings.ToUpper(strings.TrimSpace(status))]
+	if !ok {
+		return outcomePending, false
+	}
+	return outcome, true
+}
+
+// outcomeError turns a final outcome into an error with its exit code, or nil
+// for success.
+func outcomeError(outcome checkoutOutcome, status string) error {
+	codes := map[checkoutOutcome]int{
+		outcomeFailed:    exitCheckoutFailed,
+		outcomeCancelled: exitCheckoutCancelled,
+		outcomeExpired:   exitCheckoutExpired,
+	}
+	code, ok := codes[outcome]
+	if !ok {
+		return nil
+	}
+	return &exitError{code: code, err: fmt.Errorf("checkout %s (status %s)", outcome, status)}
+}
+
+func checkoutWaitCmd() *cobra.Command {
+	var interval, maxInterval, timeout time.Duration
+	var cancelOnTimeout bool
+	var orderID string
+	cmd := &cobra.Command{
+		Use:   "wait <transaction_id>",
+		Short: "Wait until a payment is completed, failed, cancelled or expired",
+		Long: "Polls the checkout status with backoff until it is final and reports the order.\n" +
+			"Exit codes: 0 success, 20 failed, 21 cancelled, 22 expired, 23 timed out.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			status, err := waitForCheckout(client, args[0], backoff{interval: interval, max: maxInterval}, timeout, cancelOnTimeout)
+			if err != nil {
+				return err
+			}
+			outcome, _ := checkoutOutcomeFor(status)
+			fmt.Println(msg("checkout.outcome", outcome, status))
+			if outcome == outcomeSuccess {
+				showPlacedOrder(client, orderID)
+			}
+			return outcomeError(outcome, status)
+		},
+	}
+	cmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "Initial time between polls")
+	cmd.Flags().DurationVar(&maxInterval, "max-interval", 30*time.Second, "Maximum time between polls")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Minute, "Give up after this long (0 waits forever)")
+	cmd.Flags().BoolVar(&cancelOnTimeout, "cancel-on-timeout", false, "Cancel the checkout when the timeout is reached")
+	cmd.Flags().StringVar(&orderID, "order", "", "Order ID from 'checkout start', to report the matching delivery")
+	return cmd
+}
+
+// backoff grows the poll interval by half while the status does not change.
+type backoff struct {
+	interval time.Duration
+	max      time.Duration
+}
+
+func (b backoff) next(current time.Duration) time.Duration {
+	next := current + current/2
+	if b.max > 0 && next > b.max {
+		next = b.max
+	}
+	return next
+}
+
+// waitForCheckout polls the checkout status until its outcome is final and
+// returns the raw status. Errors while polling are retried until the timeout.
+// On timeout the checkout is cancelled when cancel is set.
+func waitForCheckout(client *picnic.Client, transactionID string, poll backoff, timeout time.Duration, cancel bool) (string, error) {
+	deadline := time.Now().Add(timeout)
+	wait := poll.interval
+	last := ""
+	for {
+		status, err := client.GetCheckoutStatus(transactionID)
+		switch {
+		case err != nil:
+			fmt.Fprintln(os.Stderr, msg("checkout.pollError", err))
+			wait = poll.next(wait)
+		case status != last:
+			fmt.Println(msg("checkout.status", status))
+			if _, ok := checkoutOutcomeFor(status); !ok {
+				fmt.Fprintln(os.Stderr, msg("checkout.unknownStatus", status))
+			}
+			last = status
+			wait = poll.interval
+		default:
+			wait = poll.next(wait)
+		}
+		if outcome, _ := checkoutOutcomeFor(status); err == nil && outcome != outcomePending {
+			return status, nil
+		}
+
+		if timeout > 0 && time.Now().Add(wait).After(deadline) {
+			if cancel {
+				if err := client.CancelCheckout(transactionID); err != nil {
+					invalidateAuthCache()
+					return last, fmt.Errorf("checkout not finished after %s and cancelling failed: %w", timeout, err)
+				}
+				fmt.Println(msg("checkout.cancelled"))
+				return last, &exitError{code: exitCheckoutTimeout, err: fmt.Errorf("checkout not finished after %s, cancelled", timeout)}
+			}
+			return last, &exitError{code: exitCheckoutTimeout, err: fmt.Errorf("checkout not finished after %s, check with 'picnic checkout wait %s'", timeout, transactionID)}
+		}
+		time.Sleep(wait)
+	}
+}
+
+// showPlacedOrder reports the delivery of a completed checkout: the one
+// containing orderID, else the most recently created current delivery.
+func showPlacedOrder(client *picnic.Client, orderID string) {
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{picnic.CURRENT})
+	if err != nil || len(*deliveries) == 0 {
+		return
+	}
+	list := *deliveries
+	sort.SliceStable(list, func(i, j int) bool { return list[i].CreationTime > list[j].CreationTime })
+	delivery := list[0]
+	if orderID != "" {
+		for _, d := range list {
+			for _, order := range d.Orders {
+				if order.Id == orderID {
+					delivery = d
+				}
+			}
+		}
+	}
+
+	id := deliveryID(delivery)
+	total := 0
+	count := 0
+	for _, order := range delivery.Orders {
+		total += order.TotalPrice
+		count += order.TotalCount
+	}
+	fmt.Println(msg("checkout.delivery", id, delivery.Slot.WindowStart, delivery.Slot.WindowEnd))
+	if count > 0 {
+		fmt.Println(msg("checkout.total", amount(total), count))
+	}
+}
@@ -20,7 +20,7 @@ func clearCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Println("\U0001F5D1 Cart cleared")
+			fmt.Println(msg("cart.cleared"))
 			return nil
 		},
 	}
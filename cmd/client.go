@@ -28,13 +28,26 @@ type loginInput struct {
 	ClientId int    `json:"client_id"`
 }
 
+// sessionClient is the client shared by the commands of a 'picnic shell'
+// session, which sets keepSessionClient.
+var (
+	keepSessionClient bool
+	sessionClient     *picnic.Client
+)
+
 func getClient() (*picnic.Client, error) {
+	if sessionClient != nil {
+		return sessionClient, nil
+	}
 	ctx, err := getAuthContext()
 	if err != nil {
 		return nil, err
 	}
 
 	client := picnic.New(http.DefaultClient, picnic.WithCountry(ctx.Country), picnic.WithToken(ctx.Token))
+	if keepSessionClient {
+		sessionClient = client
+	}
 	return client, nil
 }
 
@@ -47,10 +60,7 @@ type authContext struct {
 func getAuthContext() (authContext, error) {
 	email := strings.TrimSpace(os.Getenv("PICNIC_EMAIL"))
 	password := os.Getenv("PICNIC_PASSWORD")
-	country := strings.TrimSpace(os.Getenv("PICNIC_COUNTRY"))
-	if country == "" {
-		country = "NL"
-	}
+	country := currentCountry()
 
 	tokenPath, err := tokenFilePath()
 	if err != nil {
@@ -102,6 +112,14 @@ func getAuthContext() (authContext, error) {
 	}, nil
 }
 
+func currentCountry() string {
+	country := strings.TrimSpace(os.Getenv("PICNIC_COUNTRY"))
+	if country == "" {
+		country = "NL"
+	}
+	return country
+}
+
 func login(country, email, password string) (string, error) {
 	baseURL := fmt.Sprintf("https://storefront-prod.%s.picnicinternational.com/api/15", strings.ToLower(country))
 	url := baseURL + "/user/login"
@@ -165,6 +183,89 @@ func preferencesFilePath() (string, error) {
 	return filepath.Join(home, ".picnic-preferences.json"), nil
 }
 
+func pricesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-prices.json"), nil
+}
+
+func catalogFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-catalog.json"), nil
+}
+
+func categoryOverridesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-categories.json"), nil
+}
+
+func rulesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-rules.json"), nil
+}
+
+func remindersFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-reminders.json"), nil
+}
+
+func followUpFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-follow-up.json"), nil
+}
+
+func shellHistoryFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-shell-history"), nil
+}
+
+func pantryFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-pantry.json"), nil
+}
+
+func pantryDeliveriesFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-pantry-deliveries.json"), nil
+}
+
+func configFilePath() (string, error) {
+	if v := strings.TrimSpace(os.Getenv("PICNIC_CONFIG_FILE")); v != "" {
+		return v, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".picnic-config.json"), nil
+}
+
 func loadAuthCache(path string) (authCache, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -185,7 +286,33 @@ func saveAuthCache(path string, cache authCache) error {
 	return os.WriteFile(path, data, 0o600)
 }
 
+// isAuthError reports whether err means the session is not, or no longer,
+// logged in. picnic-api only reports the status code in the error text.
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	text := err.Error()
+	for _, marker := range []string{"code 401", "[401]", "[AUTH_", "requires authentication"} {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidateAuthCache forgets the login after a failed request. The shell
+// keeps its session and forgets the login itself, only on auth errors, as
+// it sees the error that the command returns.
 func invalidateAuthCache() {
+	if keepSessionClient {
+		return
+	}
+	forgetLogin()
+}
+
+func forgetLogin() {
+	sessionClient = nil
 	path, err := tokenFilePath()
 	if err != nil {
 		return
@@ -203,13 +330,6 @@ func md5Hash(password string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-func formatPrice(cents int) string {
-	if cents == 0 {
-		return "?"
-	}
-	return fmt.Sprintf("\u20ac%.2f", float64(cents)/100)
-}
-
 func readCredentialsFile() (string, string, error) {
 	path := strings.TrimSpace(os.Getenv("PICNIC_AUTH_FILE"))
 	if path == "" {
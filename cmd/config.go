@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// config is the optional user configuration in ~/.picnic-config.json.
+type config struct {
+	Slots      slotConfig      `json:"slots"`
+	Notify     notifyConfig    `json:"notify"`
+	Reminders  reminderConfig  `json:"reminders"`
+	Guardrails guardrailConfig `json:"guardrails"`
+}
+
+type slotConfig struct {
+	// Policy is the default for 'slot pick' when --policy is not given.
+	Policy string `json:"policy,omitempty"`
+	// Preferred lists the windows used by the preferred-window policy.
+	Preferred []slotWindow `json:"preferred,omitempty"`
+}
+
+// loadConfig reads the user configuration. A missing file is an empty config.
+func loadConfig() (config, error) {
+	var cfg config
+	path, err := configFilePath()
+	if err != nil {
+		return cfg, err
+	}
+	if err := readJSONFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return config{}, nil
+		}
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	for i, window := range cfg.Slots.Preferred {
+		if err := window.validate(); err != nil {
+			return cfg, fmt.Errorf("invalid config %s: slots.preferred[%d]: %w", path, i, err)
+		}
+	}
+	for i, window := range cfg.Guardrails.SlotWindows {
+		if err := window.validate(); err != nil {
+			return cfg, fmt.Errorf("invalid config %s: guardrails.slot_windows[%d]: %w", path, i, err)
+		}
+	}
+	if _, err := parseLeadTimes(cfg.Reminders.LeadTimes); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: reminders.lead_times: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+func deliveryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delivery",
+		Short: "Placed orders and deliveries",
+	}
+	cmd.AddCommand(deliveryCancelCmd())
+	return cmd
+}
+
+func deliveryCancelCmd() *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "cancel <delivery_id>",
+		Short: "Cancel a placed order that has not been delivered yet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			delivery, err := client.GetDelivery(args[0])
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			showDelivery(delivery)
+
+			orders := cancellableOrders(delivery)
+			if len(orders) == 0 {
+				return fmt.Errorf("delivery %s cannot be cancelled (status %s)", args[0], delivery.Status)
+			}
+			if !yes && !confirm(msg("delivery.cancelConfirm", args[0])) {
+				return fmt.Errorf("cancellation aborted")
+			}
+
+			for _, order := range orders {
+				payload, err := cancelOrderRaw(order.Id)
+				if err != nil {
+					return err
+				}
+				fmt.Println(msg("delivery.cancelled", order.Id))
+				if len(payload) > 0 {
+					fmt.Println(payload)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	return cmd
+}
+
+func showDelivery(delivery *picnic.Delivery) {
+	id := deliveryID(*delivery)
+	fmt.Println(msg("checkout.delivery", id, delivery.Slot.WindowStart, delivery.Slot.WindowEnd))
+	fmt.Println(msg("delivery.status", delivery.Status))
+	for _, order := range delivery.Orders {
+		fmt.Println(msg("delivery.order", order.Id, amount(order.TotalPrice), order.TotalCount, order.Cancellable))
+	}
+}
+
+// cancellableOrders returns the orders of a current delivery that Picnic marks
+// as cancellable.
+func cancellableOrders(delivery *picnic.Delivery) []picnic.Order {
+	if delivery.Status != picnic.CURRENT {
+		return nil
+	}
+	var orders []picnic.Order
+	for _, order := range delivery.Orders {
+		if order.Cancellable && order.Id != "" {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
+// cancelOrderRaw cancels an order and returns the response, indented when it
+// is JSON. The picnic-api library has no cancellation call. Picnic does not
+// document its API; POST /order/{order_id}/cancel is the call cancelOrder
+// makes in the TypeScript client (github.com/MRVDH/picnic-api).
+func cancelOrderRaw(orderID string) (string, error) {
+	req, err := newRawRequest("POST", "/order/"+url.PathEscape(orderID)+"/cancel", struct{}{})
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("cancel order %s failed: status %d: %s", orderID, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		return strings.TrimSpace(string(body)), nil
+	}
+	return indented.String(), nil
+}
+
+func deliveryID(d picnic.Delivery) string {
+	if d.DeliveryId != "" {
+		return d.DeliveryId
+	}
+	return d.Id
+}
+
+// deliveryDate is when a delivery arrived, else the start of its slot, else
+// when it was ordered.
+func deliveryDate(d picnic.Delivery) time.Time {
+	for _, value := range []string{d.DeliveryTime.Start, d.Slot.WindowStart} {
+		if t, ok := parseTimestamp(value); ok {
+			return t
+		}
+	}
+	t, _ := parseTimestamp(d.CreationTime)
+	return t
+}
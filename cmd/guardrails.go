@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+// Exit codes of the checkout guardrails, one per rule so that scripts can tell
+// them apart.
+const (
+	exitMaxTotal          = 10
+	exitMaxItemQuantity   = 11
+	exitForbiddenProduct  = 12
+	exitForbiddenCategory = 13
+	exitSlotWindow        = 14
+	exitMonthlyCap        = 15
+)
+
+// guardrailConfig limits what checkout accepts. Amounts are in cents; zero or
+// empty values disable a rule.
+type guardrailConfig struct {
+	MaxTotal            int          `json:"max_total_cents,omitempty"`
+	MaxItemQuantity     int          `json:"max_item_quantity,omitempty"`
+	ForbiddenProducts   []string     `json:"forbidden_products,omitempty"`
+	ForbiddenCategories []string     `json:"forbidden_categories,omitempty"`
+	SlotWindows         []slotWindow `json:"slot_windows,omitempty"`
+	MonthlyCap          int          `json:"monthly_cap_cents,omitempty"`
+}
+
+func (r guardrailConfig) enabled() bool {
+	return r.MaxTotal > 0 || r.MaxItemQuantity > 0 || len(r.ForbiddenProducts) > 0 ||
+		len(r.ForbiddenCategories) > 0 || len(r.SlotWindows) > 0 || r.MonthlyCap > 0
+}
+
+// exitError is an error that ends the program with a specific exit code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+func guardrailError(code int, format string, args ...any) error {
+	return &exitError{code: code, err: fmt.Errorf("guardrail: "+format, args...)}
+}
+
+// guardrailClient is what checking the guardrails needs from Picnic: the
+// catalog for categories and the deliveries for the monthly cap.
+type guardrailClient interface {
+	GetMyStore() (*picnic.MyStore, error)
+	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
+}
+
+// checkGuardrails validates the cart against the configured guardrails and
+// returns the first violation.
+func checkGuardrails(client guardrailClient, cart *picnic.Order, rails guardrailConfig, now time.Time) error {
+	if rails.MaxTotal > 0 && cart.TotalPrice > rails.MaxTotal {
+		return guardrailError(exitMaxTotal, "cart total %s exceeds the maximum of %s", amount(cart.TotalPrice), amount(rails.MaxTotal))
+	}
+
+	forbidden := map[string]bool{}
+	for _, id := range rails.ForbiddenProducts {
+		forbidden[strings.TrimSpace(id)] = true
+	}
+	var categories *categoryMatcher
+	if len(rails.ForbiddenCategories) > 0 {
+		rules, err := loadCategoryRules("", currentCountry())
+		if err != nil {
+			return err
+		}
+		categories = &categoryMatcher{classifier: loadProductClassifier(client, false, rules)}
+		if err := categories.validate(rails.ForbiddenCategories); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id == "" {
+				continue
+			}
+			if rails.MaxItemQuantity > 0 && article.Quantity() > rails.MaxItemQuantity {
+				return guardrailError(exitMaxItemQuantity, "%s (%s) has quantity %d, the maximum is %d", article.Name, article.Id, article.Quantity(), rails.MaxItemQuantity)
+			}
+			if forbidden[article.Id] {
+				return guardrailError(exitForbiddenProduct, "%s (%s) is a forbidden product", article.Name, article.Id)
+			}
+			if categories != nil {
+				category := categories.classifier.classify(article.Id, article.Name)
+				for _, name := range rails.ForbiddenCategories {
+					if categories.matches(name, category) {
+						return guardrailError(exitForbiddenCategory, "%s (%s) is in forbidden category %s (%s)", article.Name, article.Id, name, category)
+					}
+				}
+				if !categories.classified(article.Id, category, rails.ForbiddenCategories) {
+					return guardrailError(exitForbiddenCategory, "%s (%s) cannot be classified, so it may be in a forbidden category", article.Name, article.Id)
+				}
+			}
+		}
+	}
+
+	if len(rails.SlotWindows) > 0 {
+		slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: cart.DeliverySlots, SelectedSlot: cart.SelectedSlot})
+		if !ok {
+			return guardrailError(exitSlotWindow, "no delivery slot selected")
+		}
+		start, okStart := parseTimestamp(slot.WindowStart)
+		end, okEnd := parseTimestamp(slot.WindowEnd)
+		allowed := false
+		for _, window := range rails.SlotWindows {
+			if okStart && okEnd && window.matches(start, end) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return guardrailError(exitSlotWindow, "slot %s - %s is outside the allowed windows", slot.WindowStart, slot.WindowEnd)
+		}
+	}
+
+	if rails.MonthlyCap > 0 {
+		spent, err := monthlySpending(client, now, cart.Id)
+		if err != nil {
+			return &exitError{code: exitMonthlyCap, err: fmt.Errorf("guardrail: cannot check the monthly cap: %w", err)}
+		}
+		if spent+cart.TotalPrice > rails.MonthlyCap {
+			return guardrailError(exitMonthlyCap, "%s spent this month plus the cart total %s exceeds the monthly cap of %s", amount(spent), amount(cart.TotalPrice), amount(rails.MonthlyCap))
+		}
+	}
+	return nil
+}
+
+// monthlySpending sums the orders of this calendar month's current and
+// completed deliveries, except the order being checked. Cancelled deliveries
+// do not count. It asks Picnic rather than the local history, which only
+// holds what analyze-orders fetched and misses orders still to be delivered.
+func monthlySpending(client guardrailClient, now time.Time, orderID string) (int, error) {
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{picnic.CURRENT, picnic.COMPLETED})
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, delivery := range *deliveries {
+		date := deliveryDate(delivery).In(now.Location())
+		if date.Year() != now.Year() || date.Month() != now.Month() {
+			continue
+		}
+		for _, order := range delivery.Orders {
+			if orderID != "" && order.Id == orderID {
+				continue
+			}
+			total += order.TotalPrice
+		}
+	}
+	return total, nil
+}
+
+// categoryMatcher matches the forbidden categories users write, such as
+// "bier", with the categories the classifier gives: a rule name or display
+// name, or a word of a Picnic catalog category such as "Bier & cider".
+type categoryMatcher struct {
+	classifier *productClassifier
+}
+
+func (m *categoryMatcher) matches(name, category string) bool {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return false
+	}
+	display := m.classifier.rules.display(category)
+	if strings.EqualFold(category, name) || strings.EqualFold(display, name) {
+		return true
+	}
+	return slices.Contains(nameWords(category), name) || slices.Contains(nameWords(display), name)
+}
+
+// classified reports whether the category of an article can be trusted to
+// clear it: it comes from an override or the catalog, or a category rule
+// matched while every forbidden category is a rule category. Anything else
+// may be in a forbidden catalog category the rules do not know.
+func (m *categoryMatcher) classified(id, category string, names []string) bool {
+	if m.classifier.overrides[id] != "" || m.classifier.catalog[id] != "" {
+		return true
+	}
+	if category == m.classifier.rules.other.Name {
+		return false
+	}
+	for _, name := range names {
+		ruleCategory := false
+		for rule := range m.classifier.rules.definitions {
+			if m.matches(name, rule) {
+				ruleCategory = true
+				break
+			}
+		}
+		if !ruleCategory {
+			return false
+		}
+	}
+	return true
+}
+
+// validate rejects forbidden categories that match no known category, as
+// those would never block anything.
+func (m *categoryMatcher) validate(names []string) error {
+	known := map[string]bool{}
+	for name := range m.classifier.rules.definitions {
+		known[name] = true
+	}
+	for _, category := range m.classifier.catalog {
+		known[category] = true
+	}
+	for _, category := range m.classifier.overrides {
+		known[category] = true
+	}
+	for _, name := range names {
+		found := false
+		for category := range known {
+			if m.matches(name, category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rules := slices.Sorted(maps.Keys(m.classifier.rules.definitions))
+			return &exitError{code: exitForbiddenCategory, err: fmt.Errorf("guardrail: forbidden category %q matches no known category; use a catalog category or one of %s", name, strings.Join(rules, ", "))}
+		}
+	}
+	return nil
+}
+
+// enforceGuardrails loads the configured guardrails and checks the cart.
+func enforceGuardrails(client *picnic.Client, cart *picnic.Order) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	return checkGuardrails(client, cart, cfg.Guardrails, time.Now())
+}
+
+// enforceOrderGuardrails checks an order that checkout already placed before
+// it is paid, as pay accepts any order ID. Paying stops when guardrails are
+// set and the order is not among the current deliveries.
+func enforceOrderGuardrails(client *picnic.Client, orderID string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Guardrails.enabled() {
+		return nil
+	}
+	order, err := placedOrder(client, orderID)
+	if err != nil {
+		return fmt.Errorf("guardrail: cannot check order %s: %w", orderID, err)
+	}
+	return checkGuardrails(client, order, cfg.Guardrails, time.Now())
+}
+
+// placedOrder finds an order among the current deliveries and returns it with
+// the slot of its delivery selected.
+func placedOrder(client interface {
+	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
+	GetDelivery(deliveryId string) (*picnic.Delivery, error)
+}, orderID string) (*picnic.Order, error) {
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{picnic.CURRENT})
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range *deliveries {
+		delivery, err := client.GetDelivery(deliveryID(summary))
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range delivery.Orders {
+			if order.Id != orderID {
+				continue
+			}
+			slot := delivery.Slot
+			slot.Selected = true
+			order.DeliverySlots = []picnic.DeliverySlot{slot}
+			order.SelectedSlot = picnic.SelectedSlot{SlotId: slot.SlotId}
+			return &order, nil
+		}
+	}
+	return nil, fmt.Errorf("order %s is not among the current deliveries", orderID)
+}
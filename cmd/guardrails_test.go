@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckGuardrailsForbiddenCategories(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PICNIC_COUNTRY", "NL")
+	rails := guardrailConfig{ForbiddenCategories: []string{"alcohol"}}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		product string
+		want    string
+	}{
+		{"allowed", "Halfvolle melk", ""},
+		{"forbidden", "Hertog Jan pils", "forbidden category alcohol"},
+		{"unclassified", "Theelichtjes", "cannot be classified"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			store.products["s3"] = store.products["s1"]
+			article := store.products["s3"]
+			article.Id, article.Name = "s3", tt.product
+			store.products["s3"] = article
+			store.cart["s3"] = 1
+
+			err := checkGuardrails(store, store.order(), rails, now)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("checkGuardrails = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("checkGuardrails = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacedOrderGuardrails(t *testing.T) {
+	store := newFakeStore()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	order, err := placedOrder(store, "o1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The order itself is part of this month's deliveries and must not be
+	// counted twice.
+	if err := checkGuardrails(store, order, guardrailConfig{MonthlyCap: 3000}, now); err != nil {
+		t.Errorf("monthly cap: %v", err)
+	}
+	morning := guardrailConfig{SlotWindows: []slotWindow{{Before: "12:00"}}}
+	err = checkGuardrails(store, order, morning, now)
+	if err == nil || !strings.Contains(err.Error(), "outside the allowed windows") {
+		t.Errorf("slot window: %v, want the slot refused", err)
+	}
+
+	if _, err := placedOrder(store, "o2"); err == nil {
+		t.Error("placedOrder found an unknown order")
+	}
+}
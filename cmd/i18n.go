@@ -0,0 +1,1205 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// translation holds one message in every supported language. Keeping the
+// languages side by side makes a missing translation obvious in review.
+type translation struct {
+	en string
+	nl string
+	de string
+	fr string
+}
+
+func (t translation) get(lang string) string {
+	switch lang {
+	case "nl":
+		return t.nl
+	case "de":
+		return t.de
+	case "fr":
+		return t.fr
+	}
+	return t.en
+}
+
+var supportedLanguages = []string{"en", "nl", "de", "fr"}
+
+var currentLanguage = "en"
+
+// setLanguage picks the output language from the --lang flag, then LANG, then
+// the Picnic country, falling back to English.
+func setLanguage(flag string) error {
+	if flag != "" {
+		lang := normalizeLanguage(flag)
+		if lang == "" {
+			return fmt.Errorf("unsupported language %q (supported: %s)", flag, strings.Join(supportedLanguages, ", "))
+		}
+		currentLanguage = lang
+		return nil
+	}
+	if lang := normalizeLanguage(os.Getenv("LANG")); lang != "" {
+		currentLanguage = lang
+		return nil
+	}
+	switch strings.ToUpper(currentCountry()) {
+	case "NL", "BE":
+		currentLanguage = "nl"
+	case "DE":
+		currentLanguage = "de"
+	case "FR":
+		currentLanguage = "fr"
+	default:
+		currentLanguage = "en"
+	}
+	return nil
+}
+
+// normalizeLanguage maps values such as "nl_NL.UTF-8" or "de-DE" to a
+// supported language code, or "" when the language is not supported.
+func normalizeLanguage(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(value, "_-.@"); i >= 0 {
+		value = value[:i]
+	}
+	for _, lang := range supportedLanguages {
+		if value == lang {
+			return lang
+		}
+	}
+	return ""
+}
+
+// msg formats the message for key in the current language.
+func msg(key string, args ...interface{}) string {
+	t, ok := messages[key]
+	if !ok {
+		return key
+	}
+	format := t.get(currentLanguage)
+	if format == "" {
+		format = t.en
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+var messages = map[string]translation{
+	// search
+	"search.none": {
+		en: "No products found for %q",
+		nl: "Geen producten gevonden voor %q",
+		de: "Keine Produkte gefunden für %q",
+		fr: "Aucun produit trouvé pour %q",
+	},
+	"search.header": {
+		en: "\U0001F50D Search results for %q:",
+		nl: "\U0001F50D Zoekresultaten voor %q:",
+		de: "\U0001F50D Suchergebnisse für %q:",
+		fr: "\U0001F50D Résultats de recherche pour %q :",
+	},
+
+	// cart
+	"cart.added": {
+		en: "\u2705 Added %dx product %s to cart",
+		nl: "\u2705 %dx product %s toegevoegd aan winkelwagen",
+		de: "\u2705 %dx Produkt %s zum Warenkorb hinzugefügt",
+		fr: "\u2705 %dx produit %s ajouté au panier",
+	},
+	"cart.removed": {
+		en: "\U0001F5D1 Removed %dx product %s from cart",
+		nl: "\U0001F5D1 %dx product %s verwijderd uit winkelwagen",
+		de: "\U0001F5D1 %dx Produkt %s aus dem Warenkorb entfernt",
+		fr: "\U0001F5D1 %dx produit %s retiré du panier",
+	},
+	"cart.cleared": {
+		en: "\U0001F5D1 Cart cleared",
+		nl: "\U0001F5D1 Winkelwagen geleegd",
+		de: "\U0001F5D1 Warenkorb geleert",
+		fr: "\U0001F5D1 Panier vidé",
+	},
+	"cart.summary": {
+		en: "\U0001F6D2 Cart: %d items | Total: %s",
+		nl: "\U0001F6D2 Winkelwagen: %d artikelen | Totaal: %s",
+		de: "\U0001F6D2 Warenkorb: %d Artikel | Gesamt: %s",
+		fr: "\U0001F6D2 Panier : %d articles | Total : %s",
+	},
+	"cart.empty": {
+		en: "\U0001F6D2 Cart is empty",
+		nl: "\U0001F6D2 Winkelwagen is leeg",
+		de: "\U0001F6D2 Warenkorb ist leer",
+		fr: "\U0001F6D2 Le panier est vide",
+	},
+	"cart.savings": {
+		en: "Savings: %s",
+		nl: "Korting: %s",
+		de: "Ersparnis: %s",
+		fr: "Économies : %s",
+	},
+	"cart.deposit": {
+		en: "Deposit: %s",
+		nl: "Statiegeld: %s",
+		de: "Pfand: %s",
+		fr: "Consigne : %s",
+	},
+	"cart.header": {
+		en: "\U0001F6D2 Shopping Cart:",
+		nl: "\U0001F6D2 Winkelwagen:",
+		de: "\U0001F6D2 Warenkorb:",
+		fr: "\U0001F6D2 Panier :",
+	},
+
+	// slots
+	"slots.none": {
+		en: "No delivery slots found",
+		nl: "Geen bezorgmomenten gevonden",
+		de: "Keine Lieferfenster gefunden",
+		fr: "Aucun créneau de livraison trouvé",
+	},
+	"slots.header": {
+		en: "Delivery slots:",
+		nl: "Bezorgmomenten:",
+		de: "Lieferfenster:",
+		fr: "Créneaux de livraison :",
+	},
+	"slots.available": {
+		en: "available",
+		nl: "beschikbaar",
+		de: "verfügbar",
+		fr: "disponible",
+	},
+	"slots.unavailable": {
+		en: "unavailable",
+		nl: "niet beschikbaar",
+		de: "nicht verfügbar",
+		fr: "indisponible",
+	},
+	"slots.selected": {
+		en: " (selected)",
+		nl: " (geselecteerd)",
+		de: " (ausgewählt)",
+		fr: " (sélectionné)",
+	},
+	"slots.minimum": {
+		en: "minimum order: %s",
+		nl: "minimale bestelling: %s",
+		de: "Mindestbestellwert: %s",
+		fr: "commande minimum : %s",
+	},
+	"slots.cutOff": {
+		en: "order before: %s",
+		nl: "bestellen voor: %s",
+		de: "bestellen bis: %s",
+		fr: "commander avant : %s",
+	},
+	"slots.reason": {
+		en: "reason: %s",
+		nl: "reden: %s",
+		de: "Grund: %s",
+		fr: "raison : %s",
+	},
+	"slots.picked": {
+		en: "Slot chosen by policy %s:",
+		nl: "Bezorgmoment gekozen volgens %s:",
+		de: "Lieferfenster gewählt nach Regel %s:",
+		fr: "Créneau choisi selon la règle %s :",
+	},
+	"slots.set": {
+		en: "Selected slot %s",
+		nl: "Bezorgmoment %s geselecteerd",
+		de: "Lieferfenster %s ausgewählt",
+		fr: "Créneau %s sélectionné",
+	},
+
+	// checkout
+	"checkout.error": {
+		en: "Checkout error: %s",
+		nl: "Fout bij afrekenen: %s",
+		de: "Fehler beim Bezahlvorgang: %s",
+		fr: "Erreur lors de la commande : %s",
+	},
+	"checkout.resolveRequired": {
+		en: "Resolve key required: %s",
+		nl: "Bevestigingssleutel vereist: %s",
+		de: "Bestätigungsschlüssel erforderlich: %s",
+		fr: "Clé de résolution requise : %s",
+	},
+	"checkout.blocking": {
+		en: "Blocking: true",
+		nl: "Blokkerend: ja",
+		de: "Blockierend: ja",
+		fr: "Bloquant : oui",
+	},
+	"checkout.outOfStock": {
+		en: "Out of stock:",
+		nl: "Niet op voorraad:",
+		de: "Nicht vorrätig:",
+		fr: "En rupture de stock :",
+	},
+	"checkout.acceptOOSHint": {
+		en: "To check out without them, use --accept-oos %s",
+		nl: "Afrekenen zonder deze artikelen: --accept-oos %s",
+		de: "Ohne diese Artikel bezahlen: --accept-oos %s",
+		fr: "Pour commander sans eux : --accept-oos %s",
+	},
+	"checkout.oosPrompt": {
+		en: "Continue without %d out-of-stock articles?",
+		nl: "Doorgaan zonder %d artikelen die niet op voorraad zijn?",
+		de: "Ohne %d nicht vorrätige Artikel fortfahren?",
+		fr: "Continuer sans les %d articles en rupture de stock ?",
+	},
+	"checkout.started": {
+		en: "Checkout started. Order ID: %s",
+		nl: "Afrekenen gestart. Bestelnummer: %s",
+		de: "Bezahlvorgang gestartet. Bestellnummer: %s",
+		fr: "Commande lancée. Numéro de commande : %s",
+	},
+	"checkout.total": {
+		en: "Total: %s | Items: %d",
+		nl: "Totaal: %s | Artikelen: %d",
+		de: "Gesamt: %s | Artikel: %d",
+		fr: "Total : %s | Articles : %d",
+	},
+	"checkout.depositLine": {
+		en: "Deposit: %dx %s %s",
+		nl: "Statiegeld: %dx %s %s",
+		de: "Pfand: %dx %s %s",
+		fr: "Consigne : %dx %s %s",
+	},
+	"checkout.status": {
+		en: "Checkout status: %s",
+		nl: "Status afrekenen: %s",
+		de: "Status des Bezahlvorgangs: %s",
+		fr: "Statut de la commande : %s",
+	},
+	"checkout.cancelled": {
+		en: "Checkout cancelled",
+		nl: "Afrekenen geannuleerd",
+		de: "Bezahlvorgang abgebrochen",
+		fr: "Commande annulée",
+	},
+	"checkout.outcome": {
+		en: "Checkout %s (status %s)",
+		nl: "Afrekenen %s (status %s)",
+		de: "Bezahlvorgang %s (Status %s)",
+		fr: "Commande %s (statut %s)",
+	},
+	"checkout.delivery": {
+		en: "Delivery %s: %s - %s",
+		nl: "Bezorging %s: %s - %s",
+		de: "Lieferung %s: %s - %s",
+		fr: "Livraison %s : %s - %s",
+	},
+	"checkout.confirm": {
+		en: "Place this order for %s?",
+		nl: "Deze bestelling plaatsen voor %s?",
+		de: "Diese Bestellung für %s aufgeben?",
+		fr: "Passer cette commande pour %s ?",
+	},
+	"checkout.resolvePrompt": {
+		en: "Accept %s and continue?",
+		nl: "%s accepteren en doorgaan?",
+		de: "%s akzeptieren und fortfahren?",
+		fr: "Accepter %s et continuer ?",
+	},
+	"checkout.pollError": {
+		en: "Could not get checkout status: %v",
+		nl: "Status afrekenen niet opgehaald: %v",
+		de: "Status des Bezahlvorgangs nicht abrufbar: %v",
+		fr: "Impossible d'obtenir le statut de la commande : %v",
+	},
+	"checkout.unknownStatus": {
+		en: "Unknown checkout status %q, waiting as if it were pending",
+		nl: "Onbekende afrekenstatus %q, wachten alsof die nog loopt",
+		de: "Unbekannter Status %q des Bezahlvorgangs, es wird weiter gewartet",
+		fr: "Statut de commande inconnu %q, attente comme s'il était en cours",
+	},
+	"payment.initiated": {
+		en: "Payment initiated. Transaction ID: %s",
+		nl: "Betaling gestart. Transactienummer: %s",
+		de: "Zahlung eingeleitet. Transaktionsnummer: %s",
+		fr: "Paiement initié. Numéro de transaction : %s",
+	},
+	"payment.redirectURL": {
+		en: "Redirect URL: %s",
+		nl: "Doorstuur-URL: %s",
+		de: "Weiterleitungs-URL: %s",
+		fr: "URL de redirection : %s",
+	},
+	"payment.method": {
+		en: "Payment method: %s",
+		nl: "Betaalmethode: %s",
+		de: "Zahlungsmethode: %s",
+		fr: "Moyen de paiement : %s",
+	},
+	"payment.stored": {
+		en: "Stored payment options (* = preferred):",
+		nl: "Opgeslagen betaalopties (* = voorkeur):",
+		de: "Gespeicherte Zahlungsoptionen (* = bevorzugt):",
+		fr: "Moyens de paiement enregistrés (* = préféré) :",
+	},
+	"payment.available": {
+		en: "Available payment methods:",
+		nl: "Beschikbare betaalmethoden:",
+		de: "Verfügbare Zahlungsmethoden:",
+		fr: "Moyens de paiement disponibles :",
+	},
+	"payment.preferred": {
+		en: "Preferred option: %s",
+		nl: "Voorkeursoptie: %s",
+		de: "Bevorzugte Option: %s",
+		fr: "Option préférée : %s",
+	},
+	"payment.none": {
+		en: "(none)",
+		nl: "(geen)",
+		de: "(keine)",
+		fr: "(aucun)",
+	},
+
+	// analyze-orders
+	"analyze.noProducts": {
+		en: "No products found in order history",
+		nl: "Geen producten gevonden in de bestelgeschiedenis",
+		de: "Keine Produkte in der Bestellhistorie gefunden",
+		fr: "Aucun produit trouvé dans l'historique des commandes",
+	},
+	"analyze.fetching": {
+		en: "\U0001F4E6 Fetching order history...",
+		nl: "\U0001F4E6 Bestelgeschiedenis ophalen...",
+		de: "\U0001F4E6 Bestellhistorie wird geladen...",
+		fr: "\U0001F4E6 Récupération de l'historique des commandes...",
+	},
+	"analyze.found": {
+		en: "Found %d deliveries",
+		nl: "%d bezorgingen gevonden",
+		de: "%d Lieferungen gefunden",
+		fr: "%d livraisons trouvées",
+	},
+	"analyze.processing": {
+		en: "Processing %d/%d...",
+		nl: "Verwerken %d/%d...",
+		de: "Verarbeite %d/%d...",
+		fr: "Traitement %d/%d...",
+	},
+	"analyze.extracted": {
+		en: "\u2705 Extracted %d product entries",
+		nl: "\u2705 %d productregels verzameld",
+		de: "\u2705 %d Produkteinträge extrahiert",
+		fr: "\u2705 %d entrées produit extraites",
+	},
+	"analyze.saved": {
+		en: "Saved to %s",
+		nl: "Opgeslagen in %s",
+		de: "Gespeichert in %s",
+		fr: "Enregistré dans %s",
+	},
+	"analyze.savedPreferences": {
+		en: "\u2705 Saved preferences to %s",
+		nl: "\u2705 Voorkeuren opgeslagen in %s",
+		de: "\u2705 Vorlieben gespeichert in %s",
+		fr: "\u2705 Préférences enregistrées dans %s",
+	},
+	"analyze.title": {
+		en: "\U0001F4CA YOUR SHOPPING HABITS",
+		nl: "\U0001F4CA JOUW WINKELGEWOONTES",
+		de: "\U0001F4CA DEINE EINKAUFSGEWOHNHEITEN",
+		fr: "\U0001F4CA VOS HABITUDES D'ACHAT",
+	},
+	"analyze.topProducts": {
+		en: "\U0001F3C6 TOP %d MOST PURCHASED PRODUCTS:",
+		nl: "\U0001F3C6 TOP %d MEEST GEKOCHTE PRODUCTEN:",
+		de: "\U0001F3C6 TOP %d AM HÄUFIGSTEN GEKAUFTE PRODUKTE:",
+		fr: "\U0001F3C6 TOP %d DES PRODUITS LES PLUS ACHETÉS :",
+	},
+	"analyze.defaults": {
+		en: "\U0001F3F7\ufe0f  DEFAULT PRODUCTS PER CATEGORY:",
+		nl: "\U0001F3F7\ufe0f  STANDAARDPRODUCTEN PER CATEGORIE:",
+		de: "\U0001F3F7\ufe0f  STANDARDPRODUKTE PRO KATEGORIE:",
+		fr: "\U0001F3F7\ufe0f  PRODUITS PAR DÉFAUT PAR CATÉGORIE :",
+	},
+	"analyze.bought": {
+		en: "ID: %s | bought %dx",
+		nl: "ID: %s | %dx gekocht",
+		de: "ID: %s | %dx gekauft",
+		fr: "ID : %s | acheté %dx",
+	},
+	"analyze.alternatives": {
+		en: "Alternatives: %s",
+		nl: "Alternatieven: %s",
+		de: "Alternativen: %s",
+		fr: "Alternatives : %s",
+	},
+	"analyze.done": {
+		en: "\u2705 Now I know what you mean by \"buy milk\" etc.!",
+		nl: "\u2705 Nu weet ik wat je bedoelt met \"koop melk\" enz.!",
+		de: "\u2705 Jetzt weiß ich, was du mit \"kauf Milch\" usw. meinst!",
+		fr: "\u2705 Je sais maintenant ce que vous voulez dire par \"acheter du lait\", etc. !",
+	},
+	"analyze.catalogUnavailable": {
+		en: "Catalog unavailable, using name rules only: %s",
+		nl: "Catalogus niet beschikbaar, alleen naamregels worden gebruikt: %s",
+		de: "Katalog nicht verfügbar, nur Namensregeln werden verwendet: %s",
+		fr: "Catalogue indisponible, seules les règles de nom sont utilisées : %s",
+	},
+	"analyze.ignoringFile": {
+		en: "Ignoring %s: %s",
+		nl: "%s wordt genegeerd: %s",
+		de: "%s wird ignoriert: %s",
+		fr: "%s est ignoré : %s",
+	},
+
+	// price-history and report
+	"prices.none": {
+		en: "No price observations found for %q",
+		nl: "Geen prijswaarnemingen gevonden voor %q",
+		de: "Keine Preisbeobachtungen gefunden für %q",
+		fr: "Aucune observation de prix trouvée pour %q",
+	},
+	"prices.header": {
+		en: "\U0001F4C8 Price history for %s [%s]",
+		nl: "\U0001F4C8 Prijsgeschiedenis van %s [%s]",
+		de: "\U0001F4C8 Preisverlauf für %s [%s]",
+		fr: "\U0001F4C8 Historique des prix pour %s [%s]",
+	},
+	"prices.summary": {
+		en: "min %s | max %s | change %s",
+		nl: "min %s | max %s | verandering %s",
+		de: "min %s | max %s | Änderung %s",
+		fr: "min %s | max %s | variation %s",
+	},
+	"prices.noStaples": {
+		en: "No staple products found, run analyze-orders first",
+		nl: "Geen vaste producten gevonden, voer eerst analyze-orders uit",
+		de: "Keine Stammprodukte gefunden, führe zuerst analyze-orders aus",
+		fr: "Aucun produit habituel trouvé, lancez d'abord analyze-orders",
+	},
+	"prices.notEnough": {
+		en: "Not enough price observations in the last %d months",
+		nl: "Niet genoeg prijswaarnemingen in de afgelopen %d maanden",
+		de: "Nicht genug Preisbeobachtungen in den letzten %d Monaten",
+		fr: "Pas assez d'observations de prix sur les %d derniers mois",
+	},
+	"prices.inflationTitle": {
+		en: "\U0001F4B8 Price changes of staples over the last %d months",
+		nl: "\U0001F4B8 Prijsveranderingen van vaste producten in de afgelopen %d maanden",
+		de: "\U0001F4B8 Preisänderungen der Stammprodukte in den letzten %d Monaten",
+		fr: "\U0001F4B8 Variations de prix des produits habituels sur les %d derniers mois",
+	},
+	"prices.inflationLine": {
+		en: "%s -> %s | min %s | max %s | ID: %s",
+		nl: "%s -> %s | min %s | max %s | ID: %s",
+		de: "%s -> %s | min %s | max %s | ID: %s",
+		fr: "%s -> %s | min %s | max %s | ID : %s",
+	},
+	"prices.inflationSummary": {
+		en: "%d of %d staples got more expensive | average change %s",
+		nl: "%d van %d vaste producten werden duurder | gemiddelde verandering %s",
+		de: "%d von %d Stammprodukten wurden teurer | durchschnittliche Änderung %s",
+		fr: "%d sur %d produits habituels sont devenus plus chers | variation moyenne %s",
+	},
+	"prices.source.delivery": {
+		en: "delivery",
+		nl: "bezorging",
+		de: "Lieferung",
+		fr: "livraison",
+	},
+	"prices.source.search": {
+		en: "search",
+		nl: "zoeken",
+		de: "Suche",
+		fr: "recherche",
+	},
+	"prices.source.cart": {
+		en: "cart",
+		nl: "winkelwagen",
+		de: "Warenkorb",
+		fr: "panier",
+	},
+
+	// suggest-restock
+	"restock.none": {
+		en: "Nothing is due before the delivery on %s",
+		nl: "Niets raakt op voor de bezorging op %s",
+		de: "Vor der Lieferung am %s geht nichts aus",
+		fr: "Rien ne manquera avant la livraison du %s",
+	},
+	"restock.header": {
+		en: "\U0001F501 Running out before the delivery on %s:",
+		nl: "\U0001F501 Raakt op voor de bezorging op %s:",
+		de: "\U0001F501 Geht vor der Lieferung am %s aus:",
+		fr: "\U0001F501 Bientôt épuisé avant la livraison du %s :",
+	},
+	"restock.detail": {
+		en: "every ~%.0f days | last bought %s",
+		nl: "elke ~%.0f dagen | laatst gekocht %s",
+		de: "alle ~%.0f Tage | zuletzt gekauft %s",
+		fr: "tous les ~%.0f jours | dernier achat %s",
+	},
+	"restock.overdue": {
+		en: "due %d days ago",
+		nl: "%d dagen geleden op",
+		de: "seit %d Tagen fällig",
+		fr: "à racheter depuis %d jours",
+	},
+	"restock.dueIn": {
+		en: "due in %d days",
+		nl: "over %d dagen op",
+		de: "in %d Tagen fällig",
+		fr: "à racheter dans %d jours",
+	},
+	"restock.dueToday": {
+		en: "due today",
+		nl: "vandaag op",
+		de: "heute fällig",
+		fr: "à racheter aujourd'hui",
+	},
+	"restock.noHistory": {
+		en: "No order history yet, run analyze-orders first",
+		nl: "Nog geen bestelgeschiedenis, voer eerst analyze-orders uit",
+		de: "Noch kein Bestellverlauf, führe zuerst analyze-orders aus",
+		fr: "Pas encore d'historique de commandes, lancez d'abord analyze-orders",
+	},
+	"restock.confirm": {
+		en: "Add %d products to the cart?",
+		nl: "%d producten toevoegen aan winkelwagen?",
+		de: "%d Produkte zum Warenkorb hinzufügen?",
+		fr: "Ajouter %d produits au panier ?",
+	},
+
+	// prompts
+	"prompt.yesNo": {
+		en: "[y/N]",
+		nl: "[j/N]",
+		de: "[j/N]",
+		fr: "[o/N]",
+	},
+
+	// cart check
+	"check.noSlot": {
+		en: "No delivery slot selected, use 'picnic slot set' first",
+		nl: "Geen bezorgmoment geselecteerd, gebruik eerst 'picnic slot set'",
+		de: "Kein Lieferfenster ausgewählt, nutze zuerst 'picnic slot set'",
+		fr: "Aucun créneau sélectionné, utilisez d'abord 'picnic slot set'",
+	},
+	"check.ok": {
+		en: "\u2705 Cart total %s meets the minimum of %s for %s",
+		nl: "\u2705 Totaal %s haalt het minimum van %s voor %s",
+		de: "\u2705 Warenkorbsumme %s erreicht den Mindestwert von %s für %s",
+		fr: "\u2705 Le total %s atteint le minimum de %s pour %s",
+	},
+	"check.below": {
+		en: "\u26A0\ufe0f Cart total %s is below the minimum of %s for %s (%s short)",
+		nl: "\u26A0\ufe0f Totaal %s ligt onder het minimum van %s voor %s (%s tekort)",
+		de: "\u26A0\ufe0f Warenkorbsumme %s liegt unter dem Mindestwert von %s für %s (%s fehlen)",
+		fr: "\u26A0\ufe0f Le total %s est inférieur au minimum de %s pour %s (il manque %s)",
+	},
+	"check.noProposal": {
+		en: "No suitable products found in your history to fill the gap",
+		nl: "Geen geschikte producten in je geschiedenis gevonden om het verschil aan te vullen",
+		de: "Keine passenden Produkte in deiner Historie gefunden, um die Lücke zu füllen",
+		fr: "Aucun produit adapté trouvé dans votre historique pour combler l'écart",
+	},
+	"check.proposal": {
+		en: "Proposed additions:",
+		nl: "Voorgestelde aanvullingen:",
+		de: "Vorgeschlagene Ergänzungen:",
+		fr: "Ajouts proposés :",
+	},
+	"check.proposalTotal": {
+		en: "Adds %s (%s above the minimum)",
+		nl: "Voegt %s toe (%s boven het minimum)",
+		de: "Fügt %s hinzu (%s über dem Mindestwert)",
+		fr: "Ajoute %s (%s au-dessus du minimum)",
+	},
+	"check.confirm": {
+		en: "Add these %d products to reach the minimum?",
+		nl: "Deze %d producten toevoegen om het minimum te halen?",
+		de: "Diese %d Produkte hinzufügen, um den Mindestwert zu erreichen?",
+		fr: "Ajouter ces %d produits pour atteindre le minimum ?",
+	},
+
+	// slot watch
+	"watch.started": {
+		en: "\U0001F440 Watching for an available slot in %s (every %s)...",
+		nl: "\U0001F440 Wachten op een beschikbaar bezorgmoment in %s (elke %s)...",
+		de: "\U0001F440 Warte auf ein freies Lieferfenster in %s (alle %s)...",
+		fr: "\U0001F440 Surveillance d'un créneau disponible dans %s (toutes les %s)...",
+	},
+	"watch.error": {
+		en: "Polling failed: %s (retrying in %s)",
+		nl: "Ophalen mislukt: %s (nieuwe poging over %s)",
+		de: "Abfrage fehlgeschlagen: %s (neuer Versuch in %s)",
+		fr: "Échec de la requête : %s (nouvel essai dans %s)",
+	},
+	"watch.found": {
+		en: "\u2705 Slot available:",
+		nl: "\u2705 Bezorgmoment beschikbaar:",
+		de: "\u2705 Lieferfenster verfügbar:",
+		fr: "\u2705 Créneau disponible :",
+	},
+	"watch.notifyAvailable": {
+		en: "Picnic slot %s - %s is available",
+		nl: "Picnic-bezorgmoment %s - %s is beschikbaar",
+		de: "Picnic-Lieferfenster %s - %s ist verfügbar",
+		fr: "Le créneau Picnic %s - %s est disponible",
+	},
+	"watch.notifySelected": {
+		en: "Picnic slot %s - %s was selected",
+		nl: "Picnic-bezorgmoment %s - %s is geselecteerd",
+		de: "Picnic-Lieferfenster %s - %s wurde ausgewählt",
+		fr: "Le créneau Picnic %s - %s a été sélectionné",
+	},
+
+	// calendar
+	"calendar.written": {
+		en: "\U0001F4C5 Calendar written to %s",
+		nl: "\U0001F4C5 Agenda opgeslagen in %s",
+		de: "\U0001F4C5 Kalender gespeichert in %s",
+		fr: "\U0001F4C5 Calendrier enregistré dans %s",
+	},
+	"calendar.serving": {
+		en: "\U0001F4C5 Serving calendar feed at %s",
+		nl: "\U0001F4C5 Agendafeed beschikbaar op %s",
+		de: "\U0001F4C5 Kalender-Feed verfügbar unter %s",
+		fr: "\U0001F4C5 Flux du calendrier disponible sur %s",
+	},
+	"calendar.delivery": {
+		en: "Picnic delivery",
+		nl: "Picnic-bezorging",
+		de: "Picnic-Lieferung",
+		fr: "Livraison Picnic",
+	},
+	"calendar.selectedSlot": {
+		en: "Picnic delivery slot (not ordered yet)",
+		nl: "Picnic-bezorgmoment (nog niet besteld)",
+		de: "Picnic-Lieferfenster (noch nicht bestellt)",
+		fr: "Créneau Picnic (pas encore commandé)",
+	},
+	"calendar.window": {
+		en: "Slot: %s - %s",
+		nl: "Bezorgmoment: %s - %s",
+		de: "Lieferfenster: %s - %s",
+		fr: "Créneau : %s - %s",
+	},
+	"calendar.eta": {
+		en: "Expected: %s - %s",
+		nl: "Verwacht: %s - %s",
+		de: "Erwartet: %s - %s",
+		fr: "Prévu : %s - %s",
+	},
+	"calendar.cutOff": {
+		en: "Order before: %s",
+		nl: "Bestellen voor: %s",
+		de: "Bestellen bis: %s",
+		fr: "Commander avant : %s",
+	},
+	"calendar.cutOffAlarm": {
+		en: "Picnic order cut-off",
+		nl: "Picnic-besteldeadline",
+		de: "Picnic-Bestellschluss",
+		fr: "Heure limite de commande Picnic",
+	},
+
+	// remind
+	"remind.remaining": {
+		en: "%s left to order for the slot starting %s (cut-off %s)",
+		nl: "Nog %s om te bestellen voor het bezorgmoment vanaf %s (deadline %s)",
+		de: "Noch %s zum Bestellen für das Lieferfenster ab %s (Bestellschluss %s)",
+		fr: "Encore %s pour commander pour le créneau de %s (limite %s)",
+	},
+	"remind.passed": {
+		en: "The cut-off for the slot starting %s has passed (%s)",
+		nl: "De deadline voor het bezorgmoment vanaf %s is verstreken (%s)",
+		de: "Der Bestellschluss für das Lieferfenster ab %s ist vorbei (%s)",
+		fr: "L'heure limite pour le créneau de %s est dépassée (%s)",
+	},
+	"remind.notify": {
+		en: "Picnic: %s left to order for %s (%d items, %s)",
+		nl: "Picnic: nog %s om te bestellen voor %s (%d artikelen, %s)",
+		de: "Picnic: noch %s zum Bestellen für %s (%d Artikel, %s)",
+		fr: "Picnic : encore %s pour commander pour %s (%d articles, %s)",
+	},
+
+	// delivery
+	"delivery.status": {
+		en: "Status: %s",
+		nl: "Status: %s",
+		de: "Status: %s",
+		fr: "Statut : %s",
+	},
+	"delivery.order": {
+		en: "Order %s: %s, %d items, cancellable: %t",
+		nl: "Bestelling %s: %s, %d artikelen, annuleerbaar: %t",
+		de: "Bestellung %s: %s, %d Artikel, stornierbar: %t",
+		fr: "Commande %s : %s, %d articles, annulable : %t",
+	},
+	"delivery.cancelConfirm": {
+		en: "Cancel delivery %s?",
+		nl: "Bezorging %s annuleren?",
+		de: "Lieferung %s stornieren?",
+		fr: "Annuler la livraison %s ?",
+	},
+	"delivery.cancelled": {
+		en: "Order %s cancelled",
+		nl: "Bestelling %s geannuleerd",
+		de: "Bestellung %s storniert",
+		fr: "Commande %s annulée",
+	},
+
+	// order follow-up
+	"order.followUp": {
+		en: "Follow-up order for delivery %s (%s - %s), possible until %s",
+		nl: "Vervolgbestelling voor bezorging %s (%s - %s), mogelijk tot %s",
+		de: "Folgebestellung für Lieferung %s (%s - %s), möglich bis %s",
+		fr: "Commande complémentaire pour la livraison %s (%s - %s), possible jusqu'à %s",
+	},
+	"order.followUpNote": {
+		en: "Cart is a follow-up order for delivery %s (until %s)",
+		nl: "Winkelwagen is een vervolgbestelling voor bezorging %s (tot %s)",
+		de: "Warenkorb ist eine Folgebestellung für Lieferung %s (bis %s)",
+		fr: "Le panier est une commande complémentaire pour la livraison %s (jusqu'à %s)",
+	},
+	"order.changes": {
+		en: "Follow-up order (the placed order stays unchanged):",
+		nl: "Vervolgbestelling (de geplaatste bestelling blijft ongewijzigd):",
+		de: "Folgebestellung (die aufgegebene Bestellung bleibt unverändert):",
+		fr: "Commande complémentaire (la commande passée reste inchangée) :",
+	},
+	"order.noChanges": {
+		en: "(empty, use 'picnic add')",
+		nl: "(leeg, gebruik 'picnic add')",
+		de: "(leer, mit 'picnic add' hinzufügen)",
+		fr: "(vide, utilisez 'picnic add')",
+	},
+	"order.alsoPlaced": {
+		en: "%d already ordered, %d in total",
+		nl: "%d al besteld, %d in totaal",
+		de: "%d bereits bestellt, %d insgesamt",
+		fr: "%d déjà commandé(s), %d au total",
+	},
+	"order.totals": {
+		en: "Placed: %s | Follow-up: %s | Delivery total: %s",
+		nl: "Geplaatst: %s | Vervolg: %s | Totaal bezorging: %s",
+		de: "Aufgegeben: %s | Folgebestellung: %s | Summe der Lieferung: %s",
+		fr: "Commandé : %s | Complément : %s | Total de la livraison : %s",
+	},
+	"order.confirm": {
+		en: "Order %s more for delivery %s?",
+		nl: "%s extra bestellen voor bezorging %s?",
+		de: "%s zusätzlich für Lieferung %s bestellen?",
+		fr: "Commander %s de plus pour la livraison %s ?",
+	},
+	"order.aborted": {
+		en: "Stopped the follow-up order for delivery %s",
+		nl: "Vervolgbestelling voor bezorging %s gestopt",
+		de: "Folgebestellung für Lieferung %s beendet",
+		fr: "Commande complémentaire pour la livraison %s arrêtée",
+	},
+
+	// buy
+	"buy.added": {
+		en: "\u2705 Added %dx %s [%s] (%s)",
+		nl: "\u2705 %dx %s [%s] toegevoegd (%s)",
+		de: "\u2705 %dx %s [%s] hinzugefügt (%s)",
+		fr: "\u2705 %dx %s [%s] ajouté (%s)",
+	},
+	"buy.source.preference": {
+		en: "your usual product",
+		nl: "je vaste product",
+		de: "dein übliches Produkt",
+		fr: "votre produit habituel",
+	},
+	"buy.source.search": {
+		en: "first search result",
+		nl: "eerste zoekresultaat",
+		de: "erstes Suchergebnis",
+		fr: "premier résultat de recherche",
+	},
+
+	// serve
+	"serve.listening": {
+		en: "\U0001F310 Serving the Picnic API at %s",
+		nl: "\U0001F310 Picnic-API beschikbaar op %s",
+		de: "\U0001F310 Picnic-API verfügbar unter %s",
+		fr: "\U0001F310 API Picnic disponible sur %s",
+	},
+
+	// tui
+	"tui.header": {
+		en: "%d items · %s · %s",
+		nl: "%d artikelen · %s · %s",
+		de: "%d Artikel · %s · %s",
+		fr: "%d articles · %s · %s",
+	},
+	"tui.noSlot": {
+		en: "no slot selected",
+		nl: "geen tijdvak gekozen",
+		de: "kein Zeitfenster gewählt",
+		fr: "aucun créneau choisi",
+	},
+	"tui.search": {
+		en: "Search",
+		nl: "Zoeken",
+		de: "Suche",
+		fr: "Recherche",
+	},
+	"tui.results": {
+		en: "Results",
+		nl: "Resultaten",
+		de: "Ergebnisse",
+		fr: "Résultats",
+	},
+	"tui.startSearch": {
+		en: "Type a product name and press enter",
+		nl: "Typ een productnaam en druk op enter",
+		de: "Produktnamen eingeben und Enter drücken",
+		fr: "Tapez un nom de produit et appuyez sur entrée",
+	},
+	"tui.noResults": {
+		en: "No products found for %q",
+		nl: "Geen producten gevonden voor %q",
+		de: "Keine Produkte gefunden für %q",
+		fr: "Aucun produit trouvé pour %q",
+	},
+	"tui.cart": {
+		en: "Cart",
+		nl: "Winkelwagen",
+		de: "Warenkorb",
+		fr: "Panier",
+	},
+	"tui.cartEmpty": {
+		en: "Cart is empty",
+		nl: "Winkelwagen is leeg",
+		de: "Warenkorb ist leer",
+		fr: "Le panier est vide",
+	},
+	"tui.total": {
+		en: "Total: %s",
+		nl: "Totaal: %s",
+		de: "Summe: %s",
+		fr: "Total : %s",
+	},
+	"tui.belowMinimum": {
+		en: "%s below the slot minimum",
+		nl: "%s onder het minimum van het tijdvak",
+		de: "%s unter dem Mindestbestellwert",
+		fr: "%s sous le minimum du créneau",
+	},
+	"tui.slots": {
+		en: "Delivery slots",
+		nl: "Bezorgmomenten",
+		de: "Lieferfenster",
+		fr: "Créneaux de livraison",
+	},
+	"tui.noSlots": {
+		en: "No delivery slots",
+		nl: "Geen bezorgmomenten",
+		de: "Keine Lieferfenster",
+		fr: "Aucun créneau de livraison",
+	},
+	"tui.minimum": {
+		en: "min %s",
+		nl: "min %s",
+		de: "min. %s",
+		fr: "min %s",
+	},
+	"tui.selected": {
+		en: "selected",
+		nl: "gekozen",
+		de: "gewählt",
+		fr: "choisi",
+	},
+	"tui.unavailable": {
+		en: "unavailable",
+		nl: "niet beschikbaar",
+		de: "nicht verfügbar",
+		fr: "indisponible",
+	},
+	"tui.added": {
+		en: "Added %s",
+		nl: "%s toegevoegd",
+		de: "%s hinzugefügt",
+		fr: "%s ajouté",
+	},
+	"tui.removed": {
+		en: "Removed %s",
+		nl: "%s verwijderd",
+		de: "%s entfernt",
+		fr: "%s retiré",
+	},
+	"tui.slotSelected": {
+		en: "Selected slot %s",
+		nl: "Tijdvak %s gekozen",
+		de: "Zeitfenster %s gewählt",
+		fr: "Créneau %s choisi",
+	},
+	"tui.slotUnavailable": {
+		en: "This slot is not available",
+		nl: "Dit tijdvak is niet beschikbaar",
+		de: "Dieses Zeitfenster ist nicht verfügbar",
+		fr: "Ce créneau n'est pas disponible",
+	},
+	"tui.needSlot": {
+		en: "Select a delivery slot first with s",
+		nl: "Kies eerst een tijdvak met s",
+		de: "Zuerst mit s ein Zeitfenster wählen",
+		fr: "Choisissez d'abord un créneau avec s",
+	},
+	"tui.confirmCheckout": {
+		en: "Check out %d items for %s, delivered %s? (y/n)",
+		nl: "%d artikelen afrekenen voor %s, bezorgd %s? (j/n)",
+		de: "%d Artikel für %s bestellen, Lieferung %s? (y/n)",
+		fr: "Commander %d articles pour %s, livrés %s ? (o/n)",
+	},
+	"tui.checkoutAborted": {
+		en: "Checkout cancelled",
+		nl: "Afrekenen geannuleerd",
+		de: "Bestellung abgebrochen",
+		fr: "Commande annulée",
+	},
+	"tui.error": {
+		en: "Error: %s",
+		nl: "Fout: %s",
+		de: "Fehler: %s",
+		fr: "Erreur : %s",
+	},
+	"tui.help": {
+		en: "/ search  tab switch  ↑↓ move  enter/+ add  - remove  s slot  c checkout  r reload  q quit",
+		nl: "/ zoeken  tab wisselen  ↑↓ kiezen  enter/+ toevoegen  - verwijderen  s tijdvak  c afrekenen  r verversen  q stoppen",
+		de: "/ suchen  Tab wechseln  ↑↓ wählen  Enter/+ hinzufügen  - entfernen  s Zeitfenster  c bestellen  r neu laden  q beenden",
+		fr: "/ chercher  tab changer  ↑↓ choisir  entrée/+ ajouter  - retirer  s créneau  c commander  r recharger  q quitter",
+	},
+	"tui.helpSearch": {
+		en: "type to search  enter search  esc back  ctrl+c quit",
+		nl: "typ om te zoeken  enter zoeken  esc terug  ctrl+c stoppen",
+		de: "tippen zum Suchen  Enter suchen  Esc zurück  Strg+C beenden",
+		fr: "tapez pour chercher  entrée chercher  échap retour  ctrl+c quitter",
+	},
+	"tui.helpSlots": {
+		en: "↑↓ move  enter select  esc close",
+		nl: "↑↓ kiezen  enter selecteren  esc sluiten",
+		de: "↑↓ wählen  Enter auswählen  Esc schließen",
+		fr: "↑↓ choisir  entrée sélectionner  échap fermer",
+	},
+	"tui.helpConfirm": {
+		en: "y check out  any other key cancels",
+		nl: "j afrekenen  andere toets annuleert",
+		de: "y bestellen  andere Taste bricht ab",
+		fr: "o commander  autre touche annule",
+	},
+
+	// units
+	"units.kg": {
+		en: "kg",
+		nl: "kg",
+		de: "kg",
+		fr: "kg",
+	},
+	"units.l": {
+		en: "l",
+		nl: "l",
+		de: "l",
+		fr: "l",
+	},
+	"units.pcs": {
+		en: "pc",
+		nl: "st",
+		de: "Stk",
+		fr: "pce",
+	},
+
+	// shell
+	"shell.welcome": {
+		en: "\U0001F6D2 Picnic shell. Type help for commands, #N for search results, exit to leave.",
+		nl: "\U0001F6D2 Picnic-shell. Typ help voor commando's, #N voor zoekresultaten, exit om te stoppen.",
+		de: "\U0001F6D2 Picnic-Shell. help zeigt Befehle, #N Suchergebnisse, exit beendet.",
+		fr: "\U0001F6D2 Shell Picnic. Tapez help pour les commandes, #N pour les résultats, exit pour quitter.",
+	},
+	"shell.noResults": {
+		en: "No search results yet, run 'search <query>' first",
+		nl: "Nog geen zoekresultaten, gebruik eerst 'search <zoekterm>'",
+		de: "Noch keine Suchergebnisse, zuerst 'search <Begriff>' ausführen",
+		fr: "Pas encore de résultats, lancez d'abord 'search <terme>'",
+	},
+
+	// recipe
+	"recipe.header": {
+		en: "\U0001F4D6 %s: %d ingredients",
+		nl: "\U0001F4D6 %s: %d ingrediënten",
+		de: "\U0001F4D6 %s: %d Zutaten",
+		fr: "\U0001F4D6 %s : %d ingrédients",
+	},
+	"recipe.untitled": {
+		en: "Recipe",
+		nl: "Recept",
+		de: "Rezept",
+		fr: "Recette",
+	},
+	"recipe.scaled": {
+		en: "Scaled from %d to %d servings",
+		nl: "Omgerekend van %d naar %d porties",
+		de: "Von %d auf %d Portionen umgerechnet",
+		fr: "Adapté de %d à %d portions",
+	},
+	"recipe.for": {
+		en: "for %s",
+		nl: "voor %s",
+		de: "für %s",
+		fr: "pour %s",
+	},
+	"recipe.packUnknown": {
+		en: "(pack size unknown, check the amount)",
+		nl: "(verpakkingsgrootte onbekend, controleer het aantal)",
+		de: "(Packungsgröße unbekannt, Menge prüfen)",
+		fr: "(taille du paquet inconnue, vérifiez la quantité)",
+	},
+	"recipe.unresolved": {
+		en: "Not found:",
+		nl: "Niet gevonden:",
+		de: "Nicht gefunden:",
+		fr: "Introuvable :",
+	},
+	"recipe.total": {
+		en: "Estimated total: %s",
+		nl: "Geschat totaal: %s",
+		de: "Geschätzte Summe: %s",
+		fr: "Total estimé : %s",
+	},
+	"recipe.confirm": {
+		en: "Add these %d products to the cart?",
+		nl: "Deze %d producten aan de winkelwagen toevoegen?",
+		de: "Diese %d Produkte in den Warenkorb legen?",
+		fr: "Ajouter ces %d produits au panier ?",
+	},
+	"recipe.added": {
+		en: "\u2705 Added %dx %s",
+		nl: "\u2705 %dx %s toegevoegd",
+		de: "\u2705 %dx %s hinzugefügt",
+		fr: "\u2705 %dx %s ajouté",
+	},
+	"recipe.inPantry": {
+		en: "\u2714 %s: in the pantry",
+		nl: "\u2714 %s: in de voorraad",
+		de: "\u2714 %s: im Vorrat",
+		fr: "\u2714 %s : dans le garde-manger",
+	},
+	"recipe.minusPantry": {
+		en: "(minus %s in the pantry)",
+		nl: "(min %s in de voorraad)",
+		de: "(abzüglich %s im Vorrat)",
+		fr: "(moins %s dans le garde-manger)",
+	},
+
+	// plan
+	"plan.estimated": {
+		en: "Cost per recipe (estimated):",
+		nl: "Kosten per recept (geschat):",
+		de: "Kosten pro Rezept (geschätzt):",
+		fr: "Coût par recette (estimé) :",
+	},
+	"plan.atCartPrices": {
+		en: "Cost per recipe at cart prices:",
+		nl: "Kosten per recept tegen winkelwagenprijzen:",
+		de: "Kosten pro Rezept zu Warenkorbpreisen:",
+		fr: "Coût par recette aux prix du panier :",
+	},
+	"plan.total": {
+		en: "Total: %s",
+		nl: "Totaal: %s",
+		de: "Summe: %s",
+		fr: "Total : %s",
+	},
+	"plan.nothingToAdd": {
+		en: "The pantry covers everything, nothing to add.",
+		nl: "De voorraad dekt alles, niets toe te voegen.",
+		de: "Der Vorrat deckt alles ab, nichts hinzuzufügen.",
+		fr: "Le garde-manger couvre tout, rien à ajouter.",
+	},
+
+	// pantry
+	"pantry.header": {
+		en: "\U0001F3E0 Pantry:",
+		nl: "\U0001F3E0 Voorraad:",
+		de: "\U0001F3E0 Vorrat:",
+		fr: "\U0001F3E0 Garde-manger :",
+	},
+	"pantry.empty": {
+		en: "The pantry is empty. It fills with completed deliveries, or use 'pantry set <item> <amount>'.",
+		nl: "De voorraad is leeg. Die vult zich met afgeronde bezorgingen, of gebruik 'pantry set <item> <hoeveelheid>'.",
+		de: "Der Vorrat ist leer. Er füllt sich mit abgeschlossenen Lieferungen, oder 'pantry set <Artikel> <Menge>' verwenden.",
+		fr: "Le garde-manger est vide. Il se remplit avec les livraisons terminées, ou utilisez 'pantry set <article> <quantité>'.",
+	},
+	"pantry.min": {
+		en: "(min %s)",
+		nl: "(min %s)",
+		de: "(min. %s)",
+		fr: "(min %s)",
+	},
+	"pantry.left": {
+		en: "%s: %s left",
+		nl: "%s: nog %s",
+		de: "%s: noch %s",
+		fr: "%s : il reste %s",
+	},
+	"pantry.nowLow": {
+		en: "\u26A0 %s is running low",
+		nl: "\u26A0 %s raakt op",
+		de: "\u26A0 %s wird knapp",
+		fr: "\u26A0 %s commence à manquer",
+	},
+	"pantry.thresholdSet": {
+		en: "%s is low below %s",
+		nl: "%s is laag onder %s",
+		de: "%s ist knapp unter %s",
+		fr: "%s manque en dessous de %s",
+	},
+	"pantry.noneLow": {
+		en: "No items are below their threshold.",
+		nl: "Geen items onder hun drempel.",
+		de: "Keine Artikel unter ihrer Schwelle.",
+		fr: "Aucun article sous son seuil.",
+	},
+	"pantry.lowHeader": {
+		en: "\u26A0 Running low (stock / threshold → packs to add):",
+		nl: "\u26A0 Raakt op (voorraad / drempel → aan te vullen verpakkingen):",
+		de: "\u26A0 Wird knapp (Vorrat / Schwelle → Packungen nachkaufen):",
+		fr: "\u26A0 Bientôt épuisé (stock / seuil → paquets à ajouter) :",
+	},
+	"pantry.confirm": {
+		en: "Add these %d items to the cart?",
+		nl: "Deze %d items aan de winkelwagen toevoegen?",
+		de: "Diese %d Artikel in den Warenkorb legen?",
+		fr: "Ajouter ces %d articles au panier ?",
+	},
+	"pantry.synced": {
+		en: "\U0001F4E6 Delivery of %s added to the pantry",
+		nl: "\U0001F4E6 Bezorging van %s aan de voorraad toegevoegd",
+		de: "\U0001F4E6 Lieferung vom %s zum Vorrat hinzugefügt",
+		fr: "\U0001F4E6 Livraison du %s ajoutée au garde-manger",
+	},
+	"pantry.firstSync": {
+		en: "First sync: only the latest delivery is counted, correct the stock with 'pantry set'.",
+		nl: "Eerste synchronisatie: alleen de laatste bezorging telt mee, corrigeer de voorraad met 'pantry set'.",
+		de: "Erste Synchronisierung: nur die letzte Lieferung zählt, den Vorrat mit 'pantry set' korrigieren.",
+		fr: "Première synchronisation : seule la dernière livraison est comptée, corrigez le stock avec 'pantry set'.",
+	},
+	"pantry.upToDate": {
+		en: "The pantry is up to date with your deliveries.",
+		nl: "De voorraad is bij met je bezorgingen.",
+		de: "Der Vorrat ist auf dem Stand deiner Lieferungen.",
+		fr: "Le garde-manger est à jour avec vos livraisons.",
+	},
+	"pantry.syncFailed": {
+		en: "Could not add new deliveries to the pantry: %v",
+		nl: "Kon nieuwe bezorgingen niet aan de voorraad toevoegen: %v",
+		de: "Neue Lieferungen konnten nicht zum Vorrat hinzugefügt werden: %v",
+		fr: "Impossible d'ajouter les nouvelles livraisons au garde-manger : %v",
+	},
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var (
+	msgCallPattern = regexp.MustCompile(`\bmsg\("([^"]+)"`)
+	verbPattern    = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?[a-zA-Z%]`)
+)
+
+func TestMessagesHaveEveryLanguage(t *testing.T) {
+	for key, tr := range messages {
+		for _, lang := range supportedLanguages {
+			if strings.TrimSpace(tr.get(lang)) == "" {
+				t.Errorf("%s: missing %s translation", key, lang)
+			}
+		}
+	}
+}
+
+func TestMessagesUseTheSameVerbs(t *testing.T) {
+	for key, tr := range messages {
+		want := verbs(tr.en)
+		for _, lang := range supportedLanguages {
+			if got := verbs(tr.get(lang)); got != want {
+				t.Errorf("%s: %s uses %s, en uses %s", key, lang, got, want)
+			}
+		}
+	}
+}
+
+func TestMessageKeysExist(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range msgCallPattern.FindAllStringSubmatch(string(data), -1) {
+			if !messageDefined(m[1]) {
+				t.Errorf("%s: message %q is not defined", file, m[1])
+			}
+		}
+	}
+}
+
+// messageDefined reports whether key is a message. Keys ending in a dot are
+// completed at run time, e.g. msg("units."+unit), and need some message with
+// that prefix.
+func messageDefined(key string) bool {
+	if _, ok := messages[key]; ok {
+		return true
+	}
+	if !strings.HasSuffix(key, ".") {
+		return false
+	}
+	for k := range messages {
+		if strings.HasPrefix(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// verbs lists the formatting verbs of a message in sorted order, as
+// translations may reorder words but must take the same arguments.
+func verbs(format string) string {
+	found := verbPattern.FindAllString(format, -1)
+	sort.Strings(found)
+	return strings.Join(found, " ")
+}
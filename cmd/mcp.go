@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+const mcpLatestProtocol = "2025-06-18"
+
+var mcpProtocols = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+// JSON-RPC error codes used by the MCP server.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// storefront is the part of picnic.Client the MCP tools and the REST server
+// use, so that they can run against another implementation.
+type storefront interface {
+	GetCart() (*picnic.Order, error)
+	AddToCart(itemId string, count int) (*picnic.Order, error)
+	RemoveFromCart(itemId string, count int) (*picnic.Order, error)
+	GetDeliverySlots() (*picnic.DeliverySlots, error)
+	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
+	GetArticleDetails(articleId string) (*picnic.ArticleDetails, error)
+	GetMyStore() (*picnic.MyStore, error)
+	GetDeliveryPosition(deliveryId string) (*picnic.DeliveryPosition, error)
+	GetDeliveryScenario(deliveryId string) (*picnic.DeliveryScenario, error)
+	SearchArticles(query string) ([]picnic.SingleArticle, error)
+}
+
+// mcpServer serves the Model Context Protocol over newline-delimited JSON-RPC.
+// The functions default to the ones the cobra commands use.
+type mcpServer struct {
+	connect       func() (storefront, error)
+	search        func(query string) ([]searchResult, error)
+	startCheckout func(cart *picnic.Order, resolveKeys, oosArticleIDs []string) (*picnic.Checkout, *checkoutIssue)
+	guardrails    func() (guardrailConfig, error)
+	tools         []mcpTool
+}
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      any       `json:"id"`
+	Result  any       `json:"result,omitempty"`
+	Error   *rpcError `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func mcpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Serve the Model Context Protocol over stdio for AI assistants",
+		Long: "Runs an MCP server on stdin/stdout. Tools: search_products, get_product, view_cart,\n" +
+			"add_to_cart, remove_from_cart, buy_by_name, list_slots, list_deliveries and checkout_start.\n" +
+			"checkout_start applies the guardrails from the config file.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return newMCPServer().serve(os.Stdin, os.Stdout)
+		},
+	}
+	return cmd
+}
+
+func newMCPServer() *mcpServer {
+	s := &mcpServer{
+		connect: func() (storefront, error) {
+			return getClient()
+		},
+		search:        searchArticlesRaw,
+		startCheckout: startCheckoutRaw,
+		guardrails: func() (guardrailConfig, error) {
+			cfg, err := loadConfig()
+			return cfg.Guardrails, err
+		},
+	}
+	s.tools = mcpTools()
+	return s
+}
+
+// serve handles requests until in is closed. Nothing else may write to out.
+func (s *mcpServer) serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+	enc := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp, ok := s.handle(line); ok {
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one message. Notifications get no response.
+func (s *mcpServer) handle(line []byte) (rpcResponse, bool) {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return errorResponse(nil, rpcParseError, "parse error: "+err.Error()), true
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return errorResponse(req.ID, rpcInvalidRequest, "invalid request"), len(req.ID) > 0
+	}
+	if len(req.ID) == 0 {
+		return rpcResponse{}, false
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		protocol := params.ProtocolVersion
+		if !mcpProtocols[protocol] {
+			protocol = mcpLatestProtocol
+		}
+		return resultResponse(req.ID, map[string]any{
+			"protocolVersion": protocol,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "picnic", "version": version},
+		}), true
+	case "ping":
+		return resultResponse(req.ID, map[string]any{}), true
+	case "tools/list":
+		return resultResponse(req.ID, map[string]any{"tools": s.tools}), true
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return errorResponse(req.ID, rpcInvalidParams, "invalid params: "+err.Error()), true
+		}
+		tool, ok := s.tool(params.Name)
+		if !ok {
+			return errorResponse(req.ID, rpcInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)), true
+		}
+		return resultResponse(req.ID, s.call(tool, params.Arguments)), true
+	}
+	return errorResponse(req.ID, rpcMethodNotFound, "method not found: "+req.Method), true
+}
+
+func (s *mcpServer) tool(name string) (mcpTool, bool) {
+	for _, tool := range s.tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+	return mcpTool{}, false
+}
+
+// call runs a tool. Failures are reported in the result with isError so that
+// the assistant can read them.
+func (s *mcpServer) call(tool mcpTool, arguments json.RawMessage) map[string]any {
+	if len(arguments) == 0 || string(arguments) == "null" {
+		arguments = json.RawMessage("{}")
+	}
+	result, err := tool.handler(s, arguments)
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]any{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, err := json.Marshal(result)
+	if err != nil {
+		return map[string]any{
+			"content": []map[string]any{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	return map[string]any{
+		"content":           []map[string]any{{"type": "text", "text": string(text)}},
+		"structuredContent": result,
+	}
+}
+
+func resultResponse(id json.RawMessage, result any) rpcResponse {
+	return rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) rpcResponse {
+	var rawID any
+	if len(id) > 0 {
+		rawID = id
+	}
+	return rpcResponse{JSONRPC: "2.0", ID: rawID, Error: &rpcError{Code: code, Message: message}}
+}
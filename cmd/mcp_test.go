@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+// fakeStore is an in-memory storefront with a cart of products by ID.
+type fakeStore struct {
+	products map[string]picnic.SingleArticle
+	cart     map[string]int
+	slot     string
+	calls    []string
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		products: map[string]picnic.SingleArticle{
+			"s1": {Id: "s1", Name: "Halfvolle melk", DisplayPrice: 129, Price: 129, UnitQuantity: "1 liter"},
+			"s2": {Id: "s2", Name: "Karnemelk", DisplayPrice: 149, Price: 149, UnitQuantity: "1 liter"},
+		},
+		cart: map[string]int{},
+	}
+}
+
+func (f *fakeStore) GetCart() (*picnic.Order, error) {
+	f.calls = append(f.calls, "GetCart")
+	return f.order(), nil
+}
+
+func (f *fakeStore) AddToCart(itemId string, count int) (*picnic.Order, error) {
+	f.calls = append(f.calls, fmt.Sprintf("AddToCart %s %d", itemId, count))
+	if _, ok := f.products[itemId]; !ok {
+		return nil, fmt.Errorf("unknown product %s", itemId)
+	}
+	f.cart[itemId] += count
+	return f.order(), nil
+}
+
+func (f *fakeStore) RemoveFromCart(itemId string, count int) (*picnic.Order, error) {
+	f.calls = append(f.calls, fmt.Sprintf("RemoveFromCart %s %d", itemId, count))
+	f.cart[itemId] -= count
+	if f.cart[itemId] <= 0 {
+		delete(f.cart, itemId)
+	}
+	return f.order(), nil
+}
+
+func (f *fakeStore) SetDeliverySlot(slotId string) (*picnic.Order, error) {
+	f.calls = append(f.calls, "SetDeliverySlot "+slotId)
+	f.slot = slotId
+	return f.order(), nil
+}
+
+func (f *fakeStore) GetDeliverySlots() (*picnic.DeliverySlots, error) {
+	return &picnic.DeliverySlots{
+		DeliverySlots: f.slots(),
+		SelectedSlot:  picnic.SelectedSlot{SlotId: f.slot},
+	}, nil
+}
+
+func (f *fakeStore) GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error) {
+	deliveries := []picnic.Delivery{{
+		DeliveryId: "d1",
+		Status:     picnic.CURRENT,
+		Slot:       picnic.DeliverySlot{SlotId: "slot1", WindowStart: "2026-10-20T18:00:00.000+02:00", WindowEnd: "2026-10-20T20:00:00.000+02:00"},
+		Orders:     []picnic.Order{{Id: "o1", TotalPrice: 2500}},
+	}}
+	return &deliveries, nil
+}
+
+func (f *fakeStore) GetDelivery(deliveryId string) (*picnic.Delivery, error) {
+	deliveries, _ := f.GetDeliveries(nil)
+	for _, delivery := range *deliveries {
+		if deliveryID(delivery) == deliveryId {
+			return &delivery, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown delivery %s", deliveryId)
+}
+
+func (f *fakeStore) GetArticleDetails(articleId string) (*picnic.ArticleDetails, error) {
+	article, ok := f.products[articleId]
+	if !ok {
+		return nil, fmt.Errorf("unknown product %s", articleId)
+	}
+	details := &picnic.ArticleDetails{Id: article.Id, Name: article.Name, UnitQuantity: article.UnitQuantity}
+	details.PriceInfo.Price = article.Price
+	return details, nil
+}
+
+func (f *fakeStore) GetMyStore() (*picnic.MyStore, error) {
+	return &picnic.MyStore{}, nil
+}
+
+func (f *fakeStore) GetDeliveryPosition(deliveryId string) (*picnic.DeliveryPosition, error) {
+	return &picnic.DeliveryPosition{}, nil
+}
+
+func (f *fakeStore) GetDeliveryScenario(deliveryId string) (*picnic.DeliveryScenario, error) {
+	return &picnic.DeliveryScenario{}, nil
+}
+
+func (f *fakeStore) SearchArticles(query string) ([]picnic.SingleArticle, error) {
+	var articles []picnic.SingleArticle
+	for _, id := range []string{"s1", "s2"} {
+		article := f.products[id]
+		if strings.Contains(strings.ToLower(article.Name), strings.ToLower(query)) {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+func (f *fakeStore) search(query string) ([]searchResult, error) {
+	articles, err := f.SearchArticles(query)
+	return clientSearchResults(articles), err
+}
+
+func (f *fakeStore) slots() []picnic.DeliverySlot {
+	return []picnic.DeliverySlot{
+		{SlotId: "slot1", WindowStart: "2026-10-20T18:00:00.000+02:00", WindowEnd: "2026-10-20T20:00:00.000+02:00", CutOffTime: "2026-10-19T22:00:00.000+02:00", IsAvailable: true, MinimumOrderValue: 3500},
+		{SlotId: "slot2", WindowStart: "2026-10-21T08:00:00.000+02:00", WindowEnd: "2026-10-21T10:00:00.000+02:00", CutOffTime: "2026-10-20T22:00:00.000+02:00", IsAvailable: true, MinimumOrderValue: 3500},
+	}
+}
+
+func (f *fakeStore) order() *picnic.Order {
+	order := &picnic.Order{DeliverySlots: f.slots(), SelectedSlot: picnic.SelectedSlot{SlotId: f.slot}}
+	for _, id := range slices.Sorted(maps.Keys(f.cart)) {
+		count := f.cart[id]
+		if count == 0 {
+			continue
+		}
+		article := f.products[id]
+		order.Items = append(order.Items, picnic.OrderLine{
+			Id: "line-" + id,
+			Items: []picnic.OrderArticle{{
+				Id:           article.Id,
+				Name:         article.Name,
+				DisplayPrice: article.DisplayPrice * count,
+				UnitQuantity: article.UnitQuantity,
+				Decorators:   []picnic.Decorator{{Type: "QUANTITY", Quantity: count}},
+			}},
+		})
+		order.TotalCount += count
+		order.TotalPrice += article.DisplayPrice * count
+	}
+	return order
+}
+
+// newTestMCPServer returns a server on a fake store. Prices and preferences
+// are kept in a temporary home directory.
+func newTestMCPServer(t *testing.T, store *fakeStore, rails guardrailConfig) (*mcpServer, *[]picnic.Order) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	var checkedOut []picnic.Order
+	s := &mcpServer{
+		connect: func() (storefront, error) { return store, nil },
+		search:  store.search,
+		startCheckout: func(cart *picnic.Order, resolveKeys, oosArticleIDs []string) (*picnic.Checkout, *checkoutIssue) {
+			checkedOut = append(checkedOut, *cart)
+			return &picnic.Checkout{OrderId: "order1", TotalPrice: cart.TotalPrice, TotalCount: cart.TotalCount}, nil
+		},
+		guardrails: func() (guardrailConfig, error) { return rails, nil },
+		tools:      mcpTools(),
+	}
+	return s, &checkedOut
+}
+
+// mcpSession drives serve over a pipe, one request at a time.
+type mcpSession struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	nextID int
+}
+
+func startMCPSession(t *testing.T, s *mcpServer) *mcpSession {
+	t.Helper()
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := s.serve(inReader, outWriter)
+		outWriter.Close()
+		done <- err
+	}()
+	t.Cleanup(func() {
+		inWriter.Close()
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+	return &mcpSession{t: t, in: inWriter, out: bufio.NewScanner(outReader)}
+}
+
+func (m *mcpSession) call(method string, params any) map[string]any {
+	m.t.Helper()
+	m.nextID++
+	request, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": m.nextID, "method": method, "params": params})
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	if _, err := m.in.Write(append(request, '\n')); err != nil {
+		m.t.Fatal(err)
+	}
+	if !m.out.Scan() {
+		m.t.Fatalf("%s: no response: %v", method, m.out.Err())
+	}
+	var resp struct {
+		ID     int            `json:"id"`
+		Result map[string]any `json:"result"`
+		Error  *rpcError      `json:"error"`
+	}
+	if err := json.Unmarshal(m.out.Bytes(), &resp); err != nil {
+		m.t.Fatalf("%s: %v: %s", method, err, m.out.Bytes())
+	}
+	if resp.ID != m.nextID {
+		m.t.Fatalf("%s: response id %d, want %d", method, resp.ID, m.nextID)
+	}
+	if resp.Error != nil {
+		m.t.Fatalf("%s: error %d: %s", method, resp.Error.Code, resp.Error.Message)
+	}
+	return resp.Result
+}
+
+// callTool calls a tool and returns its structured result, or the error text
+// when the tool failed.
+func (m *mcpSession) callTool(name string, arguments map[string]any) (map[string]any, string) {
+	m.t.Helper()
+	result := m.call("tools/call", map[string]any{"name": name, "arguments": arguments})
+	if result["isError"] == true {
+		content := result["content"].([]any)
+		return nil, content[0].(map[string]any)["text"].(string)
+	}
+	structured, ok := result["structuredContent"].(map[string]any)
+	if !ok {
+		m.t.Fatalf("%s: no structured content: %v", name, result)
+	}
+	return structured, ""
+}
+
+func TestMCPServeTools(t *testing.T) {
+	store := newFakeStore()
+	s, checkedOut := newTestMCPServer(t, store, guardrailConfig{MaxTotal: 10000})
+	session := startMCPSession(t, s)
+
+	initialized := session.call("initialize", map[string]any{"protocolVersion": "2025-03-26"})
+	if initialized["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocolVersion = %v", initialized["protocolVersion"])
+	}
+
+	listed := session.call("tools/list", nil)["tools"].([]any)
+	schemas := map[string]map[string]any{}
+	for _, raw := range listed {
+		tool := raw.(map[string]any)
+		name := tool["name"].(string)
+		for _, field := range []string{"inputSchema", "outputSchema"} {
+			schema, ok := tool[field].(map[string]any)
+			if !ok || schema["type"] != "object" {
+				t.Errorf("%s: %s is not an object schema: %v", name, field, tool[field])
+			}
+		}
+		schemas[name] = tool["outputSchema"].(map[string]any)
+	}
+
+	calls := []struct {
+		tool      string
+		arguments map[string]any
+		check     func(result map[string]any) error
+	}{
+		{"search_products", map[string]any{"query": "melk", "limit": 1}, func(r map[string]any) error {
+			products := r["products"].([]any)
+			if len(products) != 1 || products[0].(map[string]any)["id"] != "s1" {
+				return fmt.Errorf("products = %v", products)
+			}
+			return nil
+		}},
+		{"get_product", map[string]any{"product_id": "s2"}, func(r map[string]any) error {
+			if r["name"] != "Karnemelk" || r["price_cents"] != 149.0 {
+				return fmt.Errorf("product = %v", r)
+			}
+			return nil
+		}},
+		{"add_to_cart", map[string]any{"product_id": "s1", "count": 3}, func(r map[string]any) error {
+			if r["total_count"] != 3.0 {
+				return fmt.Errorf("cart = %v", r)
+			}
+			return nil
+		}},
+		{"remove_from_cart", map[string]any{"product_id": "s1"}, func(r map[string]any) error {
+			if r["total_count"] != 2.0 {
+				return fmt.Errorf("cart = %v", r)
+			}
+			return nil
+		}},
+		{"buy_by_name", map[string]any{"name": "karnemelk"}, func(r map[string]any) error {
+			product := r["product"].(map[string]any)
+			if product["id"] != "s2" || product["source"] != matchSearch {
+				return fmt.Errorf("product = %v", product)
+			}
+			return nil
+		}},
+		{"view_cart", nil, func(r map[string]any) error {
+			if r["total_price_cents"] != 407.0 || len(r["items"].([]any)) != 2 {
+				return fmt.Errorf("cart = %v", r)
+			}
+			return nil
+		}},
+		{"list_slots", map[string]any{"available_only": true}, func(r map[string]any) error {
+			if len(r["slots"].([]any)) != 2 {
+				return fmt.Errorf("slots = %v", r["slots"])
+			}
+			return nil
+		}},
+		{"list_deliveries", map[string]any{"status": "current"}, func(r map[string]any) error {
+			deliveries := r["deliveries"].([]any)
+			if len(deliveries) != 1 || deliveries[0].(map[string]any)["total_price_cents"] != 2500.0 {
+				return fmt.Errorf("deliveries = %v", deliveries)
+			}
+			return nil
+		}},
+		{"checkout_start", map[string]any{"resolve_keys": []string{"age_verified"}}, func(r map[string]any) error {
+			if r["started"] != true || r["order_id"] != "order1" {
+				return fmt.Errorf("checkout = %v", r)
+			}
+			return nil
+		}},
+	}
+	called := map[string]bool{}
+	for _, c := range calls {
+		called[c.tool] = true
+		result, errText := session.callTool(c.tool, c.arguments)
+		if errText != "" {
+			t.Errorf("%s: %s", c.tool, errText)
+			continue
+		}
+		for _, field := range schemas[c.tool]["required"].([]any) {
+			if _, ok := result[field.(string)]; !ok {
+				t.Errorf("%s: result lacks required %s: %v", c.tool, field, result)
+			}
+		}
+		if err := c.check(result); err != nil {
+			t.Errorf("%s: %v", c.tool, err)
+		}
+	}
+	for name := range schemas {
+		if !called[name] {
+			t.Errorf("tool %s is not tested", name)
+		}
+	}
+	if len(*checkedOut) != 1 {
+		t.Errorf("checked out %d times, want 1", len(*checkedOut))
+	}
+}
+
+func TestMCPCheckoutStartRefusesGuardrailViolation(t *testing.T) {
+	store := newFakeStore()
+	store.cart["s1"] = 5
+	s, checkedOut := newTestMCPServer(t, store, guardrailConfig{MaxItemQuantity: 4})
+	session := startMCPSession(t, s)
+
+	result, errText := session.callTool("checkout_start", nil)
+	if !strings.Contains(errText, "guardrail:") {
+		t.Fatalf("checkout_start = %v, %q, want a guardrail error", result, errText)
+	}
+	if len(*checkedOut) != 0 {
+		t.Errorf("checkout started despite the guardrail")
+	}
+}
@@ -0,0 +1,670 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+// mcpTool is a tool as listed by tools/list, with the function that runs it.
+type mcpTool struct {
+	Name         string         `json:"name"`
+	Description  string         `json:"description"`
+	InputSchema  map[string]any `json:"inputSchema"`
+	OutputSchema map[string]any `json:"outputSchema"`
+	handler      func(s *mcpServer, args json.RawMessage) (any, error)
+}
+
+type mcpProduct struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Price     int    `json:"price_cents"`
+	PriceText string `json:"price"`
+	// PriceMissing is set when the price is not known, rather than zero.
+	PriceMissing bool   `json:"price_missing,omitempty"`
+	Unit         string `json:"unit,omitempty"`
+}
+
+type mcpProductDetails struct {
+	mcpProduct
+	Description      string `json:"description,omitempty"`
+	Promotion        string `json:"promotion,omitempty"`
+	Deposit          int    `json:"deposit_cents"`
+	MaxOrderQuantity int    `json:"max_order_quantity"`
+}
+
+type mcpCartItem struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price_cents"`
+}
+
+type mcpCart struct {
+	Items          []mcpCartItem `json:"items"`
+	TotalCount     int           `json:"total_count"`
+	TotalPrice     int           `json:"total_price_cents"`
+	TotalPriceText string        `json:"total_price"`
+	Savings        int           `json:"savings_cents"`
+	Deposit        int           `json:"deposit_cents"`
+	SelectedSlotID string        `json:"selected_slot_id,omitempty"`
+}
+
+type mcpSlot struct {
+	SlotID       string `json:"slot_id"`
+	WindowStart  string `json:"window_start"`
+	WindowEnd    string `json:"window_end"`
+	CutOffTime   string `json:"cut_off_time"`
+	Available    bool   `json:"available"`
+	Selected     bool   `json:"selected"`
+	MinimumOrder int    `json:"minimum_order_cents"`
+}
+
+type mcpDelivery struct {
+	DeliveryID  string `json:"delivery_id"`
+	Status      string `json:"status"`
+	WindowStart string `json:"window_start"`
+	WindowEnd   string `json:"window_end"`
+	EtaStart    string `json:"eta_start,omitempty"`
+	EtaEnd      string `json:"eta_end,omitempty"`
+	TotalPrice  int    `json:"total_price_cents"`
+}
+
+type mcpCheckoutIssue struct {
+	Code          string   `json:"code"`
+	Title         string   `json:"title,omitempty"`
+	Message       string   `json:"message"`
+	ResolveKey    string   `json:"resolve_key,omitempty"`
+	Blocking      bool     `json:"blocking"`
+	OosArticleIds []string `json:"oos_article_ids,omitempty"`
+}
+
+type mcpCheckout struct {
+	Started    bool              `json:"started"`
+	OrderID    string            `json:"order_id,omitempty"`
+	TotalPrice int               `json:"total_price_cents"`
+	TotalCount int               `json:"total_count"`
+	Issue      *mcpCheckoutIssue `json:"issue,omitempty"`
+}
+
+func mcpTools() []mcpTool {
+	productID := schemaString("Picnic product ID, e.g. s1018231")
+	count := schemaInteger("Number of items", 1)
+	return []mcpTool{
+		{
+			Name:        "search_products",
+			Description: "Search the Picnic assortment. Returns products with their IDs and prices.",
+			InputSchema: schemaObject(map[string]any{
+				"query": schemaString("Search term, e.g. \"halfvolle melk\""),
+				"limit": schemaInteger("Maximum number of products (default 10)", 1),
+			}, "query"),
+			OutputSchema: schemaObject(map[string]any{
+				"products": schemaArray(productSchema()),
+			}, "products"),
+			handler: mcpSearchProducts,
+		},
+		{
+			Name:        "get_product",
+			Description: "Get the details of a product: price, unit, description and promotion.",
+			InputSchema: schemaObject(map[string]any{"product_id": productID}, "product_id"),
+			OutputSchema: withProperties(productSchema(), map[string]any{
+				"description":        schemaString("Product description"),
+				"promotion":          schemaString("Promotion text"),
+				"deposit_cents":      schemaInteger("Deposit in cents", 0),
+				"max_order_quantity": schemaInteger("Maximum quantity per order, 0 if unknown", 0),
+			}, "deposit_cents", "max_order_quantity"),
+			handler: mcpGetProduct,
+		},
+		{
+			Name:         "view_cart",
+			Description:  "Show the cart: items, totals and the selected delivery slot.",
+			InputSchema:  schemaObject(map[string]any{}),
+			OutputSchema: cartSchema(),
+			handler:      mcpViewCart,
+		},
+		{
+			Name:        "add_to_cart",
+			Description: "Add a product to the cart by ID. Returns the updated cart.",
+			InputSchema: schemaObject(map[string]any{
+				"product_id": productID,
+				"count":      count,
+			}, "product_id"),
+			OutputSchema: cartSchema(),
+			handler:      mcpAddToCart,
+		},
+		{
+			Name:        "remove_from_cart",
+			Description: "Remove a product from the cart by ID. Returns the updated cart.",
+			InputSchema: schemaObject(map[string]any{
+				"product_id": productID,
+				"count":      count,
+			}, "product_id"),
+			OutputSchema: cartSchema(),
+			handler:      mcpRemoveFromCart,
+		},
+		{
+			Name:        "buy_by_name",
+			Description: "Add the usual product for a name such as \"melk\" to the cart, learned from order history, else the first search result.",
+			InputSchema: schemaObject(map[string]any{
+				"name":  schemaString("What to buy, e.g. \"melk\""),
+				"count": count,
+			}, "name"),
+			OutputSchema: schemaObject(map[string]any{
+				"product": schemaObject(map[string]any{
+					"id":            schemaString("Product ID"),
+					"name":          schemaString("Product name"),
+					"price_cents":   schemaInteger("Last known price in cents", 0),
+					"price_missing": schemaBoolean("Set when the price is unknown rather than zero"),
+					"unit":          schemaString("Unit quantity, e.g. 1 liter"),
+					"source":        schemaEnum("How the product was chosen", matchPreference, matchSearch),
+				}, "id", "name", "price_cents", "source"),
+				"cart": cartSchema(),
+			}, "product", "cart"),
+			handler: mcpBuyByName,
+		},
+		{
+			Name:        "list_slots",
+			Description: "List delivery slots with cut-off times and minimum order values.",
+			InputSchema: schemaObject(map[string]any{
+				"available_only": schemaBoolean("Only list slots that can be selected"),
+			}),
+			OutputSchema: schemaObject(map[string]any{
+				"slots": schemaArray(schemaObject(map[string]any{
+					"slot_id":             schemaString("Slot ID"),
+					"window_start":        schemaString("Start of the delivery window"),
+					"window_end":          schemaString("End of the delivery window"),
+					"cut_off_time":        schemaString("Order before this time"),
+					"available":           schemaBoolean("Whether the slot can be selected"),
+					"selected":            schemaBoolean("Whether the slot is selected for the cart"),
+					"minimum_order_cents": schemaInteger("Minimum order value in cents", 0),
+				}, "slot_id", "window_start", "window_end", "cut_off_time", "available", "selected", "minimum_order_cents")),
+			}, "slots"),
+			handler: mcpListSlots,
+		},
+		{
+			Name:        "list_deliveries",
+			Description: "List deliveries, by default the upcoming ones.",
+			InputSchema: schemaObject(map[string]any{
+				"status": schemaEnum("Delivery status (default CURRENT)", string(picnic.CURRENT), string(picnic.COMPLETED), string(picnic.CANCELLED)),
+			}),
+			OutputSchema: schemaObject(map[string]any{
+				"deliveries": schemaArray(schemaObject(map[string]any{
+					"delivery_id":       schemaString("Delivery ID"),
+					"status":            schemaString("Delivery status"),
+					"window_start":      schemaString("Start of the delivery window"),
+					"window_end":        schemaString("End of the delivery window"),
+					"eta_start":         schemaString("Start of the expected arrival"),
+					"eta_end":           schemaString("End of the expected arrival"),
+					"total_price_cents": schemaInteger("Total of the orders in cents", 0),
+				}, "delivery_id", "status", "window_start", "window_end", "total_price_cents")),
+			}, "deliveries"),
+			handler: mcpListDeliveries,
+		},
+		{
+			Name: "checkout_start",
+			Description: "Start checkout for the cart after checking the configured guardrails. " +
+				"Returns the order ID, or the issue to resolve. Does not pay.",
+			InputSchema: schemaObject(map[string]any{
+				"resolve_keys": schemaArray(schemaString("Resolve key to accept, e.g. age_verified")),
+				"accept_oos":   schemaArray(schemaString("Out-of-stock article ID to check out without")),
+			}),
+			OutputSchema: schemaObject(map[string]any{
+				"started":           schemaBoolean("Whether checkout started"),
+				"order_id":          schemaString("Order ID to pay"),
+				"total_price_cents": schemaInteger("Order total in cents", 0),
+				"total_count":       schemaInteger("Number of items", 0),
+				"issue": schemaObject(map[string]any{
+					"code":            schemaString("Issue code"),
+					"title":           schemaString("Issue title"),
+					"message":         schemaString("Issue message"),
+					"resolve_key":     schemaString("Key to pass in resolve_keys to accept the issue"),
+					"blocking":        schemaBoolean("Whether the issue cannot be resolved"),
+					"oos_article_ids": schemaArray(schemaString("Out-of-stock article ID")),
+				}, "code", "message", "blocking"),
+			}, "started", "total_price_cents", "total_count"),
+			handler: mcpCheckoutStart,
+		},
+	}
+}
+
+func mcpSearchProducts(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+	results, err := s.search(args.Query)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	recordSearchPrices(results)
+	products := []mcpProduct{}
+	for _, article := range results {
+		if len(products) == args.Limit {
+			break
+		}
+		products = append(products, newMCPProduct(article.Id, article.Name, article.price(), article.UnitQuantity))
+	}
+	return struct {
+		Products []mcpProduct `json:"products"`
+	}{products}, nil
+}
+
+func mcpGetProduct(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		ProductID string `json:"product_id"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	details, err := client.GetArticleDetails(args.ProductID)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	return mcpProductDetails{
+		mcpProduct:       newMCPProduct(details.Id, details.Name, amount(details.PriceInfo.Price), details.UnitQuantity),
+		Description:      details.Description.Main,
+		Promotion:        details.Labels.Promo.Text,
+		Deposit:          details.PriceInfo.Deposit,
+		MaxOrderQuantity: details.MaxOrderQuantity,
+	}, nil
+}
+
+func mcpViewCart(s *mcpServer, raw json.RawMessage) (any, error) {
+	if err := decodeToolArgs(raw, &struct{}{}); err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := client.GetCart()
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	recordCartPrices(cart)
+	return newMCPCart(cart), nil
+}
+
+func mcpAddToCart(s *mcpServer, raw json.RawMessage) (any, error) {
+	return mcpChangeCart(s, raw, func(client storefront, id string, count int) (*picnic.Order, error) {
+		return client.AddToCart(id, count)
+	})
+}
+
+func mcpRemoveFromCart(s *mcpServer, raw json.RawMessage) (any, error) {
+	return mcpChangeCart(s, raw, func(client storefront, id string, count int) (*picnic.Order, error) {
+		return client.RemoveFromCart(id, count)
+	})
+}
+
+func mcpChangeCart(s *mcpServer, raw json.RawMessage, change func(storefront, string, int) (*picnic.Order, error)) (any, error) {
+	var args struct {
+		ProductID string `json:"product_id"`
+		Count     int    `json:"count"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.ProductID) == "" {
+		return nil, fmt.Errorf("product_id is required")
+	}
+	if args.Count == 0 {
+		args.Count = 1
+	}
+	if args.Count < 0 {
+		return nil, fmt.Errorf("count must be positive")
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := change(client, args.ProductID, args.Count)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	return newMCPCart(cart), nil
+}
+
+func mcpBuyByName(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if args.Count == 0 {
+		args.Count = 1
+	}
+	if args.Count < 0 {
+		return nil, fmt.Errorf("count must be positive")
+	}
+	match, err := findProductByName(args.Name, s.search)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := client.AddToCart(match.ID, args.Count)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	return struct {
+		Product productMatch `json:"product"`
+		Cart    mcpCart      `json:"cart"`
+	}{match, newMCPCart(cart)}, nil
+}
+
+func mcpListSlots(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		AvailableOnly bool `json:"available_only"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	slots, err := client.GetDeliverySlots()
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	list := slots.DeliverySlots
+	if args.AvailableOnly {
+		list = slotFilter{available: true}.apply(list)
+	}
+	result := []mcpSlot{}
+	for _, slot := range list {
+		result = append(result, mcpSlot{
+			SlotID:       slot.SlotId,
+			WindowStart:  slot.WindowStart,
+			WindowEnd:    slot.WindowEnd,
+			CutOffTime:   slot.CutOffTime,
+			Available:    slot.IsAvailable,
+			Selected:     slot.Selected || slot.SlotId == slots.SelectedSlot.SlotId,
+			MinimumOrder: slot.MinimumOrderValue,
+		})
+	}
+	return struct {
+		Slots []mcpSlot `json:"slots"`
+	}{result}, nil
+}
+
+func mcpListDeliveries(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		Status string `json:"status"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	status := picnic.DeliveryStatus(strings.ToUpper(args.Status))
+	switch status {
+	case "":
+		status = picnic.CURRENT
+	case picnic.CURRENT, picnic.COMPLETED, picnic.CANCELLED:
+	default:
+		return nil, fmt.Errorf("invalid status %q", args.Status)
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{status})
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	result := []mcpDelivery{}
+	for _, d := range *deliveries {
+		id := deliveryID(d)
+		total := 0
+		for _, order := range d.Orders {
+			total += order.TotalPrice
+		}
+		result = append(result, mcpDelivery{
+			DeliveryID:  id,
+			Status:      string(d.Status),
+			WindowStart: d.Slot.WindowStart,
+			WindowEnd:   d.Slot.WindowEnd,
+			EtaStart:    d.Eta2.Start,
+			EtaEnd:      d.Eta2.End,
+			TotalPrice:  total,
+		})
+	}
+	return struct {
+		Deliveries []mcpDelivery `json:"deliveries"`
+	}{result}, nil
+}
+
+func mcpCheckoutStart(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		ResolveKeys []string `json:"resolve_keys"`
+		AcceptOOS   []string `json:"accept_oos"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	cart, err := client.GetCart()
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	if cart.TotalCount == 0 {
+		return nil, fmt.Errorf("cart is empty")
+	}
+	rails, err := s.guardrails()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGuardrails(client, cart, rails, time.Now()); err != nil {
+		return nil, err
+	}
+
+	checkout, issue := s.startCheckout(cart, trimmedValues(args.ResolveKeys), trimmedValues(args.AcceptOOS))
+	if issue != nil {
+		if issue.Code == "" {
+			return nil, issue
+		}
+		return mcpCheckout{
+			TotalPrice: cart.TotalPrice,
+			TotalCount: cart.TotalCount,
+			Issue: &mcpCheckoutIssue{
+				Code:          issue.Code,
+				Title:         issue.Title,
+				Message:       issue.Message,
+				ResolveKey:    issue.ResolveKey,
+				Blocking:      issue.Blocking,
+				OosArticleIds: issue.OosArticleIds,
+			},
+		}, nil
+	}
+	return mcpCheckout{
+		Started:    true,
+		OrderID:    checkout.OrderId,
+		TotalPrice: checkout.TotalPrice,
+		TotalCount: checkout.TotalCount,
+	}, nil
+}
+
+func (s *mcpServer) storefront() (storefront, error) {
+	return s.connect()
+}
+
+// invalidArgumentError reports arguments a tool rejects before calling Picnic.
+type invalidArgumentError struct {
+	err error
+}
+
+func (e *invalidArgumentError) Error() string { return e.err.Error() }
+func (e *invalidArgumentError) Unwrap() error { return e.err }
+
+// decodeToolArgs decodes the arguments strictly so that misspelled arguments
+// are reported instead of ignored.
+func decodeToolArgs(raw json.RawMessage, value any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(value); err != nil {
+		return &invalidArgumentError{fmt.Errorf("invalid arguments: %w", err)}
+	}
+	return nil
+}
+
+func newMCPProduct(id, name string, price money, unit string) mcpProduct {
+	return mcpProduct{
+		ID:           id,
+		Name:         name,
+		Price:        price.Cents,
+		PriceText:    price.String(),
+		PriceMissing: price.Missing,
+		Unit:         strings.TrimSpace(unit),
+	}
+}
+
+func newMCPCart(cart *picnic.Order) mcpCart {
+	result := mcpCart{
+		Items:          []mcpCartItem{},
+		TotalCount:     cart.TotalCount,
+		TotalPrice:     cart.TotalPrice,
+		TotalPriceText: amount(cart.TotalPrice).String(),
+		Savings:        cart.TotalSavings,
+		Deposit:        cart.TotalDeposit,
+		SelectedSlotID: cart.SelectedSlot.SlotId,
+	}
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id == "" {
+				continue
+			}
+			result.Items = append(result.Items, mcpCartItem{
+				ID:       article.Id,
+				Name:     article.Name,
+				Quantity: max(article.Quantity(), 1),
+				Price:    article.DisplayPrice,
+			})
+		}
+	}
+	return result
+}
+
+func productSchema() map[string]any {
+	return schemaObject(map[string]any{
+		"id":            schemaString("Product ID"),
+		"name":          schemaString("Product name"),
+		"price_cents":   schemaInteger("Price in cents", 0),
+		"price":         schemaString("Formatted price, ? when unknown"),
+		"price_missing": schemaBoolean("Set when the price is unknown rather than zero"),
+		"unit":          schemaString("Unit quantity, e.g. 1 liter"),
+	}, "id", "name", "price_cents", "price")
+}
+
+func cartSchema() map[string]any {
+	return schemaObject(map[string]any{
+		"items": schemaArray(schemaObject(map[string]any{
+			"id":          schemaString("Product ID"),
+			"name":        schemaString("Product name"),
+			"quantity":    schemaInteger("Quantity", 1),
+			"price_cents": schemaInteger("Price in cents", 0),
+		}, "id", "name", "quantity", "price_cents")),
+		"total_count":       schemaInteger("Number of items", 0),
+		"total_price_cents": schemaInteger("Total in cents", 0),
+		"total_price":       schemaString("Formatted total"),
+		"savings_cents":     schemaInteger("Savings in cents", 0),
+		"deposit_cents":     schemaInteger("Deposit in cents", 0),
+		"selected_slot_id":  schemaString("Selected delivery slot ID"),
+	}, "items", "total_count", "total_price_cents", "total_price", "savings_cents", "deposit_cents")
+}
+
+func schemaObject(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// withProperties returns a copy of an object schema with more properties.
+func withProperties(schema map[string]any, properties map[string]any, required ...string) map[string]any {
+	merged := map[string]any{}
+	for name, property := range schema["properties"].(map[string]any) {
+		merged[name] = property
+	}
+	for name, property := range properties {
+		merged[name] = property
+	}
+	req, _ := schema["required"].([]string)
+	return schemaObject(merged, append(append([]string{}, req...), required...)...)
+}
+
+func schemaString(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+func schemaInteger(description string, minimum int) map[string]any {
+	return map[string]any{"type": "integer", "description": description, "minimum": minimum}
+}
+
+func schemaNumber(description string) map[string]any {
+	return map[string]any{"type": "number", "description": description}
+}
+
+func schemaBoolean(description string) map[string]any {
+	return map[string]any{"type": "boolean", "description": description}
+}
+
+func schemaEnum(description string, values ...string) map[string]any {
+	return map[string]any{"type": "string", "description": description, "enum": values}
+}
+
+func schemaArray(items map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": items}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// money is an amount in minor units (cents) in the currency of the Picnic
+// country. A zero value is a known amount of zero; amounts the API did not
+// provide are marked missing so they are not shown as free.
+type money struct {
+	Cents    int
+	Currency string
+	Missing  bool
+}
+
+var countryCurrencies = map[string]string{
+	"NL": "EUR",
+	"BE": "EUR",
+	"DE": "EUR",
+	"FR": "EUR",
+}
+
+var currencySymbols = map[string]string{
+	"EUR": "€",
+}
+
+type moneyLocale struct {
+	decimal string
+	group   string
+	// symbolAfter places the symbol after the amount ("1,29 €").
+	symbolAfter bool
+	// symbolSpace separates a leading symbol from the amount ("€ 1,29").
+	symbolSpace bool
+	// signAfterSymbol writes negative amounts as "€ -1,29".
+	signAfterSymbol bool
+}
+
+// moneyLocales are keyed by the account country, as amounts are written the
+// way Picnic writes them there, whatever the output language. Other countries
+// are written like NL.
+var moneyLocales = map[string]moneyLocale{
+	"NL": {decimal: ",", group: ".", symbolSpace: true, signAfterSymbol: true},
+	"BE": {decimal: ",", group: ".", symbolSpace: true, signAfterSymbol: true},
+	"DE": {decimal: ",", group: ".", symbolAfter: true},
+	"FR": {decimal: ",", group: "\u202f", symbolAfter: true},
+}
+
+// amount returns a known amount in the currency of the current country.
+func amount(cents int) money {
+	return money{Cents: cents, Currency: currentCurrency()}
+}
+
+func missingAmount() money {
+	return money{Currency: currentCurrency(), Missing: true}
+}
+
+func currentCurrency() string {
+	if currency, ok := countryCurrencies[strings.ToUpper(currentCountry())]; ok {
+		return currency
+	}
+	return "EUR"
+}
+
+// Neg returns the negated amount, e.g. to show savings as a deduction.
+func (m money) Neg() money {
+	m.Cents = -m.Cents
+	return m
+}
+
+func (m money) String() string {
+	if m.Missing {
+		return "?"
+	}
+	locale, ok := moneyLocales[strings.ToUpper(currentCountry())]
+	if !ok {
+		locale = moneyLocales["NL"]
+	}
+	symbol, ok := currencySymbols[m.Currency]
+	if !ok {
+		symbol = m.Currency
+	}
+
+	cents := m.Cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	number := groupThousands(cents/100, locale.group) + locale.decimal + fmt.Sprintf("%02d", cents%100)
+
+	if locale.symbolAfter {
+		return sign + number + " " + symbol
+	}
+	space := ""
+	if locale.symbolSpace {
+		space = " "
+	}
+	if locale.signAfterSymbol {
+		return symbol + space + sign + number
+	}
+	return sign + symbol + space + number
+}
+
+func groupThousands(value int, separator string) string {
+	digits := strconv.Itoa(value)
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
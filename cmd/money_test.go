@@ -0,0 +1,44 @@
+package cmd
+
+import "testing"
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		country string
+		amount  money
+		want    string
+	}{
+		{"NL", amount(129), "€ 1,29"},
+		{"NL", amount(-129), "€ -1,29"},
+		{"NL", amount(123456789), "€ 1.234.567,89"},
+		{"NL", amount(5), "€ 0,05"},
+		{"NL", missingAmount(), "?"},
+		{"", amount(100000), "€ 1.000,00"},
+		{"BE", amount(-250), "€ -2,50"},
+		{"DE", amount(129), "1,29\u00a0€"},
+		{"DE", amount(-123456), "-1.234,56\u00a0€"},
+		{"DE", missingAmount(), "?"},
+		{"FR", amount(123456), "1\u202f234,56\u00a0€"},
+		{"FR", amount(-99), "-0,99\u00a0€"},
+		{"UK", amount(100000), "€ 1.000,00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.want, func(t *testing.T) {
+			t.Setenv("PICNIC_COUNTRY", tt.country)
+			if got := tt.amount.String(); got != tt.want {
+				t.Errorf("%d cents = %q, want %q", tt.amount.Cents, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyIgnoresLanguage(t *testing.T) {
+	t.Setenv("PICNIC_COUNTRY", "NL")
+	defer func(lang string) { currentLanguage = lang }(currentLanguage)
+	for _, lang := range []string{"en", "nl", "de", "fr"} {
+		currentLanguage = lang
+		if got := amount(123456).String(); got != "€ 1.234,56" {
+			t.Errorf("%s: %q, want € 1.234,56", lang, got)
+		}
+	}
+}
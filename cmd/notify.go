@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// notifyConfig configures how events such as a slot becoming available are
+// announced. Both a command and a webhook may be set.
+type notifyConfig struct {
+	// Command runs through "sh -c" with the event in PICNIC_* variables.
+	Command string `json:"command,omitempty"`
+	// Webhook receives the event as a JSON POST.
+	Webhook string `json:"webhook,omitempty"`
+}
+
+func (n notifyConfig) empty() bool {
+	return strings.TrimSpace(n.Command) == "" && strings.TrimSpace(n.Webhook) == ""
+}
+
+func addNotifyFlags(cmd *cobra.Command, hooks *notifyConfig) {
+	cmd.Flags().StringVar(&hooks.Command, "notify-command", "", "Command to run on notification (default notify.command from config)")
+	cmd.Flags().StringVar(&hooks.Webhook, "notify-webhook", "", "URL to POST notifications to (default notify.webhook from config)")
+}
+
+// notification is the payload sent to hooks.
+type notification struct {
+	Event   string            `json:"event"`
+	Message string            `json:"message"`
+	Time    string            `json:"time"`
+	Data    map[string]string `json:"data,omitempty"`
+}
+
+// notify sends the event to every configured hook, returning the first error
+// after trying all of them.
+func notify(hooks notifyConfig, event, message string, data map[string]string) error {
+	n := notification{
+		Event:   event,
+		Message: message,
+		Time:    time.Now().Format(time.RFC3339),
+		Data:    data,
+	}
+	var firstErr error
+	if strings.TrimSpace(hooks.Command) != "" {
+		if err := runNotifyCommand(hooks.Command, n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if strings.TrimSpace(hooks.Webhook) != "" {
+		if err := postNotifyWebhook(hooks.Webhook, n); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func runNotifyCommand(command string, n notification) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"PICNIC_EVENT="+n.Event,
+		"PICNIC_MESSAGE="+n.Message,
+		"PICNIC_TIME="+n.Time,
+	)
+	for key, value := range n.Data {
+		cmd.Env = append(cmd.Env, "PICNIC_"+strings.ToUpper(key)+"="+value)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify command failed: %w", err)
+	}
+	return nil
+}
+
+func postNotifyWebhook(url string, n notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notify webhook failed: status %d", resp.StatusCode)
+	}
+	return nil
+}
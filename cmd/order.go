@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// followUpSession marks the cart as a follow-up order for the slot of a placed
+// delivery. Picnic has no call to reopen a placed order, so the follow-up is a
+// second order for the same slot; the placed order stays as it is.
+type followUpSession struct {
+	DeliveryID string `json:"delivery_id"`
+	SlotID     string `json:"slot_id"`
+	CutOffTime string `json:"cut_off_time"`
+}
+
+// placedArticle is the name and total quantity of an article in an order.
+type placedArticle struct {
+	Name     string
+	Quantity int
+}
+
+func orderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "order",
+		Short: "Follow-up orders for placed deliveries",
+	}
+	cmd.AddCommand(orderFollowUpCmd())
+	return cmd
+}
+
+func orderFollowUpCmd() *cobra.Command {
+	var confirmOrder bool
+	var abort bool
+	var yes bool
+	var interval, timeout time.Duration
+	cmd := &cobra.Command{
+		Use:     "follow-up <delivery_id>",
+		Aliases: []string{"edit"},
+		Short:   "Order more for the slot of a placed delivery before its cut-off",
+		Long: "Starts a second order for the slot of a placed delivery: the slot is selected for the\n" +
+			"cart, which must be empty, and 'picnic add' fills it until the cut-off. Run again to see\n" +
+			"the follow-up next to the placed order, with --confirm to check it out as a separate\n" +
+			"order, or --abort to stop. The placed order itself cannot be changed or reduced.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
+			deliveryID := args[0]
+			session, err := loadFollowUp()
+			if err != nil {
+				return err
+			}
+			if abort {
+				if session == nil || session.DeliveryID != deliveryID {
+					return fmt.Errorf("no follow-up order for delivery %s", deliveryID)
+				}
+				if err := clearFollowUp(); err != nil {
+					return err
+				}
+				fmt.Println(msg("order.aborted", deliveryID))
+				return nil
+			}
+			if session != nil && session.DeliveryID != deliveryID {
+				return fmt.Errorf("already ordering a follow-up for delivery %s, finish it or run 'picnic order follow-up %s --abort'", session.DeliveryID, session.DeliveryID)
+			}
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			delivery, err := client.GetDelivery(deliveryID)
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			if delivery.Status != picnic.CURRENT {
+				return fmt.Errorf("delivery %s is %s and can no longer be changed", deliveryID, delivery.Status)
+			}
+			starting := session == nil
+			if starting {
+				session = &followUpSession{
+					DeliveryID: deliveryID,
+					SlotID:     delivery.Slot.SlotId,
+					CutOffTime: delivery.Slot.CutOffTime,
+				}
+			}
+			if err := session.checkCutOff(time.Now()); err != nil {
+				_ = clearFollowUp()
+				return err
+			}
+
+			cart, err := client.GetCart()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			// The whole cart becomes the follow-up order, so it must not hold
+			// items that were meant for something else.
+			if starting && cart.TotalCount > 0 {
+				return fmt.Errorf("the cart is not empty; check it out or run 'picnic clear' before a follow-up order")
+			}
+			if cart.SelectedSlot.SlotId != session.SlotID {
+				cart, err = client.SetDeliverySlot(session.SlotID)
+				if err != nil {
+					invalidateAuthCache()
+					return err
+				}
+			}
+			if err := saveFollowUp(session); err != nil {
+				return err
+			}
+			fmt.Println(msg("order.followUp", deliveryID, delivery.Slot.WindowStart, delivery.Slot.WindowEnd, session.CutOffTime))
+			showFollowUp(delivery, cart)
+			if !confirmOrder {
+				return nil
+			}
+
+			if cart.TotalCount == 0 {
+				return fmt.Errorf("nothing added to delivery %s, use 'picnic add' first", deliveryID)
+			}
+			if err := enforceGuardrails(client, cart); err != nil {
+				return err
+			}
+			if !yes && !confirm(msg("order.confirm", amount(cart.TotalPrice), deliveryID)) {
+				return fmt.Errorf("follow-up order not confirmed")
+			}
+			if err := session.checkCutOff(time.Now()); err != nil {
+				_ = clearFollowUp()
+				return err
+			}
+			if err := placeOrder(client, cart, checkoutOptions{yes: yes, interval: interval, timeout: timeout}); err != nil {
+				return err
+			}
+			return clearFollowUp()
+		},
+	}
+	cmd.Flags().BoolVar(&confirmOrder, "confirm", false, "Check out the follow-up order")
+	cmd.Flags().BoolVar(&abort, "abort", false, "Stop the follow-up order; the cart is kept as it is")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Time between payment status polls")
+	cmd.Flags().DurationVar(&timeout, "timeout", 15*time.Minute, "Give up waiting for the payment after this long")
+	return cmd
+}
+
+// checkCutOff fails once the cut-off of the placed delivery has passed.
+func (s followUpSession) checkCutOff(now time.Time) error {
+	cutOff, ok := parseTimestamp(s.CutOffTime)
+	if !ok {
+		return fmt.Errorf("delivery %s has no cut-off time, so no follow-up order can be placed", s.DeliveryID)
+	}
+	if !now.Before(cutOff) {
+		return fmt.Errorf("the cut-off for delivery %s passed at %s, no follow-up order can be placed", s.DeliveryID, s.CutOffTime)
+	}
+	return nil
+}
+
+// noteFollowUp is called by the commands that change the cart. While a
+// follow-up order is open the cart belongs to it, so they note that, or fail
+// once the cut-off of its delivery has passed.
+func noteFollowUp() error {
+	session, err := activeFollowUp()
+	if err != nil || session == nil {
+		return err
+	}
+	fmt.Println(msg("order.followUpNote", session.DeliveryID, session.CutOffTime))
+	return nil
+}
+
+// activeFollowUp returns the follow-up order, if any, and stops it once the
+// cut-off has passed.
+func activeFollowUp() (*followUpSession, error) {
+	session, err := loadFollowUp()
+	if err != nil || session == nil {
+		return nil, err
+	}
+	if err := session.checkCutOff(time.Now()); err != nil {
+		_ = clearFollowUp()
+		return nil, fmt.Errorf("%w; follow-up order stopped", err)
+	}
+	return session, nil
+}
+
+// showFollowUp prints the follow-up order next to the placed one. Articles
+// that are in both show the quantity the delivery will bring in total.
+func showFollowUp(delivery *picnic.Delivery, cart *picnic.Order) {
+	placed := map[string]placedArticle{}
+	placedTotal := 0
+	for _, order := range delivery.Orders {
+		placedTotal += order.TotalPrice
+		for _, line := range order.Items {
+			for _, article := range line.Items {
+				if article.Id == "" {
+					continue
+				}
+				p := placed[article.Id]
+				p.Name = article.Name
+				p.Quantity += max(article.Quantity(), 1)
+				placed[article.Id] = p
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(msg("order.changes"))
+	added := cartArticleQuantities(cart)
+	if len(added) == 0 {
+		fmt.Println("  " + msg("order.noChanges"))
+	}
+	ids := make([]string, 0, len(added))
+	for id := range added {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		a := added[id]
+		if p, ok := placed[id]; ok {
+			fmt.Printf("  + %dx %s (%s)\n", a.Quantity, a.Name, msg("order.alsoPlaced", p.Quantity, p.Quantity+a.Quantity))
+		} else {
+			fmt.Printf("  + %dx %s\n", a.Quantity, a.Name)
+		}
+	}
+	fmt.Println()
+	fmt.Println(msg("order.totals", amount(placedTotal), amount(cart.TotalPrice), amount(placedTotal+cart.TotalPrice)))
+}
+
+func cartArticleQuantities(cart *picnic.Order) map[string]placedArticle {
+	articles := map[string]placedArticle{}
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id == "" {
+				continue
+			}
+			a := articles[article.Id]
+			a.Name = article.Name
+			a.Quantity += max(article.Quantity(), 1)
+			articles[article.Id] = a
+		}
+	}
+	return articles
+}
+
+func loadFollowUp() (*followUpSession, error) {
+	path, err := followUpFilePath()
+	if err != nil {
+		return nil, err
+	}
+	var session followUpSession
+	if err := readJSONFile(path, &session); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func saveFollowUp(session *followUpSession) error {
+	path, err := followUpFilePath()
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(path, session)
+}
+
+func clearFollowUp() error {
+	path, err := followUpFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,544 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// pantryItem is the stock of a product at home. Quantity and Threshold are
+// in kg, l or pcs when the pack size is known, else in packs. Pack is the
+// unit quantity of one pack as Picnic lists it.
+type pantryItem struct {
+	ID        string  `json:"id,omitempty"`
+	Name      string  `json:"name"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	Pack      string  `json:"pack,omitempty"`
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// pantry is keyed by product ID, or by lower-case name for items without one.
+type pantry map[string]pantryItem
+
+func pantryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pantry",
+		Short: "Track what is at home, fed by completed deliveries",
+		Long: "Keeps the stock at home in ~/.picnic-pantry.json. Completed deliveries are added\n" +
+			"once each when the pantry is listed or synced; the first sync only counts the latest\n" +
+			"delivery. Amounts are plain numbers in the unit of the item, packs such as 2x, or\n" +
+			"quantities such as 500g or 1,5l.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadSyncedPantry()
+			if err != nil {
+				return err
+			}
+			showPantry(items)
+			return nil
+		},
+	}
+	cmd.AddCommand(pantrySyncCmd())
+	cmd.AddCommand(pantryUseCmd())
+	cmd.AddCommand(pantrySetCmd())
+	cmd.AddCommand(pantryThresholdCmd())
+	cmd.AddCommand(pantryLowCmd())
+	return cmd
+}
+
+func pantrySyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Add completed deliveries to the pantry",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadPantry()
+			if err != nil {
+				return err
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			added, err := syncPantry(client, items)
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			if added == 0 {
+				fmt.Println(msg("pantry.upToDate"))
+			}
+			return savePantry(items)
+		},
+	}
+}
+
+func pantryUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <item> [amount]",
+		Short: "Take an amount of an item out of the pantry (default one piece or pack)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadPantry()
+			if err != nil {
+				return err
+			}
+			key, err := items.find(args[0])
+			if err != nil {
+				return err
+			}
+			item := items[key]
+			used := item.defaultUse()
+			if len(args) == 2 {
+				if used, err = item.parseAmount(args[1]); err != nil {
+					return err
+				}
+			}
+			item.Quantity = max(item.Quantity-used, 0)
+			items[key] = item
+			if err := savePantry(items); err != nil {
+				return err
+			}
+			fmt.Println(msg("pantry.left", item.Name, item.stockText()))
+			if item.isLow() {
+				fmt.Println(msg("pantry.nowLow", item.Name))
+			}
+			return nil
+		},
+	}
+}
+
+func pantrySetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <item> <amount>",
+		Short: "Set the stock of an item, e.g. after counting",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadPantry()
+			if err != nil {
+				return err
+			}
+			key, item := items.findOrNew(args[0])
+			if item.Quantity, err = item.parseAmount(args[1]); err != nil {
+				return err
+			}
+			items[key] = item
+			if err := savePantry(items); err != nil {
+				return err
+			}
+			fmt.Println(msg("pantry.left", item.Name, item.stockText()))
+			return nil
+		},
+	}
+}
+
+func pantryThresholdCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "threshold <item> <amount>",
+		Short: "Set the stock below which an item is low (0 stops tracking it)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadPantry()
+			if err != nil {
+				return err
+			}
+			key, item := items.findOrNew(args[0])
+			if item.Threshold, err = item.parseAmount(args[1]); err != nil {
+				return err
+			}
+			items[key] = item
+			if err := savePantry(items); err != nil {
+				return err
+			}
+			fmt.Println(msg("pantry.thresholdSet", item.Name, ingredient{Quantity: item.Threshold, Unit: item.Unit}.amountText()))
+			return nil
+		},
+	}
+}
+
+func pantryLowCmd() *cobra.Command {
+	var add, yes bool
+	cmd := &cobra.Command{
+		Use:   "low",
+		Short: "List items below their threshold and optionally add them to the cart",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			items, err := loadSyncedPantry()
+			if err != nil {
+				return err
+			}
+			low := lowPantryItems(items)
+			if len(low) == 0 {
+				fmt.Println(msg("pantry.noneLow"))
+				return nil
+			}
+			fmt.Printf("%s\n\n", msg("pantry.lowHeader"))
+			for _, l := range low {
+				fmt.Printf("  %s: %s / %s → %dx\n", l.Item.Name, l.Item.stockText(),
+					ingredient{Quantity: l.Item.Threshold, Unit: l.Item.Unit}.amountText(), l.Packs)
+			}
+
+			if !add {
+				return nil
+			}
+			if !yes && !confirm(msg("pantry.confirm", len(low))) {
+				return fmt.Errorf("low items not added")
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			var last *picnic.Order
+			for _, l := range low {
+				// Items tracked by name only take the product bought under
+				// that name, else the first search result, never the top
+				// product of a category.
+				id, name := l.Item.ID, l.Item.Name
+				if id == "" {
+					match, err := findProductByName(l.Item.Name, searchArticlesRaw)
+					if err != nil {
+						if isAuthError(err) {
+							invalidateAuthCache()
+						}
+						return err
+					}
+					id, name = match.ID, match.Name
+				}
+				order, err := client.AddToCart(id, l.Packs)
+				if err != nil {
+					invalidateAuthCache()
+					return fmt.Errorf("adding %s: %w", l.Item.Name, err)
+				}
+				fmt.Println(msg("recipe.added", l.Packs, name))
+				last = order
+			}
+			showCartSummary(last)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&add, "add", false, "Add the low items to the cart")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation when adding")
+	return cmd
+}
+
+func loadPantry() (pantry, error) {
+	path, err := pantryFilePath()
+	if err != nil {
+		return nil, err
+	}
+	items := pantry{}
+	if err := readJSONFile(path, &items); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return items, nil
+}
+
+func savePantry(items pantry) error {
+	path, err := pantryFilePath()
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(path, items)
+}
+
+// loadSyncedPantry loads the pantry and adds new completed deliveries. When
+// that fails the stock on file is used.
+func loadSyncedPantry() (pantry, error) {
+	items, err := loadPantry()
+	if err != nil {
+		return nil, err
+	}
+	client, err := getClient()
+	if err == nil {
+		var added int
+		if added, err = syncPantry(client, items); err != nil {
+			invalidateAuthCache()
+		} else if added > 0 {
+			err = savePantry(items)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, msg("pantry.syncFailed", err))
+	}
+	return items, nil
+}
+
+// syncPantry adds the articles of completed deliveries that were not added
+// before and returns how many deliveries were added. The first sync only
+// adds the latest delivery, as the ones before are probably used up.
+func syncPantry(client interface {
+	GetDeliveries(filter []picnic.DeliveryStatus) (*[]picnic.Delivery, error)
+	GetDelivery(deliveryId string) (*picnic.Delivery, error)
+}, items pantry) (int, error) {
+	path, err := pantryDeliveriesFilePath()
+	if err != nil {
+		return 0, err
+	}
+	var synced []string
+	first := false
+	if err := readJSONFile(path, &synced); errors.Is(err, fs.ErrNotExist) {
+		first = true
+	} else if err != nil {
+		return 0, err
+	}
+	seen := map[string]bool{}
+	for _, id := range synced {
+		seen[id] = true
+	}
+
+	deliveries, err := client.GetDeliveries([]picnic.DeliveryStatus{picnic.COMPLETED})
+	if err != nil {
+		return 0, err
+	}
+	var pending []picnic.Delivery
+	for _, d := range *deliveries {
+		if id := deliveryID(d); id != "" && !seen[id] {
+			pending = append(pending, d)
+		}
+	}
+	if first && len(pending) > 1 {
+		sort.Slice(pending, func(i, j int) bool { return deliveryDate(pending[i]).After(deliveryDate(pending[j])) })
+		for _, d := range pending[1:] {
+			synced = append(synced, deliveryID(d))
+		}
+		pending = pending[:1]
+		fmt.Println(msg("pantry.firstSync"))
+	}
+
+	added := 0
+	for _, d := range pending {
+		detail, err := client.GetDelivery(deliveryID(d))
+		if err != nil {
+			return added, err
+		}
+		addDeliveryToPantry(items, detail)
+		synced = append(synced, deliveryID(d))
+		added++
+		fmt.Println(msg("pantry.synced", deliveryDate(d).Format("2006-01-02")))
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err := writeJSONFile(path, synced); err != nil {
+		return added, err
+	}
+	return added, nil
+}
+
+func addDeliveryToPantry(items pantry, delivery *picnic.Delivery) {
+	for _, order := range delivery.Orders {
+		for _, line := range order.Items {
+			for _, article := range line.Items {
+				if article.Type != "ORDER_ARTICLE" || article.Id == "" || article.Name == "" {
+					continue
+				}
+				qty := article.Quantity()
+				if qty == 0 {
+					qty = 1
+				}
+				key := article.Id
+				item, ok := items[key]
+				if !ok {
+					key = strings.ToLower(article.Name)
+					if item, ok = items[key]; !ok {
+						key = article.Id
+					}
+				}
+				item.ID, item.Name = article.Id, article.Name
+				if article.UnitQuantity != "" {
+					item.Pack = strings.TrimSpace(article.UnitQuantity)
+				}
+				item.addPacks(float64(qty))
+				if key != article.Id {
+					delete(items, key)
+				}
+				items[article.Id] = item
+			}
+		}
+	}
+}
+
+// addPacks adds packs to the stock, in kg, l or pcs when the pack size is
+// known. Stock counted in packs is converted once the pack size is known.
+func (item *pantryItem) addPacks(packs float64) {
+	size, ok := parseUnitQuantity(item.Pack)
+	if !ok {
+		if item.Unit == "" {
+			item.Unit = unitPack
+		}
+		if item.Unit == unitPack {
+			item.Quantity += packs
+		}
+		return
+	}
+	if item.Unit == "" || item.Unit == unitPack {
+		item.Quantity *= size.Amount
+		item.Threshold *= size.Amount
+		item.Unit = size.Unit
+	}
+	if item.Unit == size.Unit {
+		item.Quantity += packs * size.Amount
+	}
+}
+
+// parseAmount reads an amount for the item in its unit: a plain number in
+// that unit, a number of packs such as "2x", or a quantity such as "500g".
+// An item without a unit takes the unit of the amount.
+func (item *pantryItem) parseAmount(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	value, unit := 0.0, ""
+	number := func(s string) (float64, bool) {
+		n, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+		return n, err == nil && n >= 0
+	}
+	if n, ok := number(strings.TrimSuffix(strings.ToLower(text), "x")); ok && strings.HasSuffix(strings.ToLower(text), "x") {
+		value, unit = n, unitPack
+	} else if n, ok := number(text); ok {
+		value, unit = n, item.Unit
+		if unit == "" {
+			unit = unitPack
+		}
+	} else if q, ok := parseUnitQuantity(text); ok {
+		value, unit = q.Amount, q.Unit
+	} else {
+		return 0, fmt.Errorf("invalid amount %q, use e.g. 2, 2x, 500g or 1,5l", text)
+	}
+
+	size, sized := parseUnitQuantity(item.Pack)
+	switch {
+	case item.Unit == "" || unit == item.Unit:
+		item.Unit = unit
+		return value, nil
+	case unit == unitPack && sized && size.Unit == item.Unit:
+		return value * size.Amount, nil
+	case item.Unit == unitPack && sized && size.Unit == unit:
+		item.addPacks(0)
+		return value, nil
+	}
+	return 0, fmt.Errorf("%s is counted in %s, not in %s", item.Name, item.Unit, unit)
+}
+
+// defaultUse is what using an item takes without an amount: one piece, or
+// one pack.
+func (item pantryItem) defaultUse() float64 {
+	if size, ok := parseUnitQuantity(item.Pack); ok && item.Unit == size.Unit && item.Unit != unitPiece {
+		return size.Amount
+	}
+	return 1
+}
+
+func (item pantryItem) isLow() bool {
+	return item.Threshold > 0 && item.Quantity < item.Threshold
+}
+
+func (item pantryItem) stockText() string {
+	if item.Quantity <= 0 {
+		return "0"
+	}
+	return ingredient{Quantity: item.Quantity, Unit: item.Unit}.amountText()
+}
+
+// find looks an item up by product ID, name, or a unique part of the name.
+func (p pantry) find(query string) (string, error) {
+	if _, ok := p[query]; ok {
+		return query, nil
+	}
+	lower := strings.ToLower(strings.TrimSpace(query))
+	var partial []string
+	for key, item := range p {
+		name := strings.ToLower(item.Name)
+		if name == lower || key == lower {
+			return key, nil
+		}
+		if strings.Contains(name, lower) {
+			partial = append(partial, key)
+		}
+	}
+	switch len(partial) {
+	case 0:
+		return "", fmt.Errorf("%q is not in the pantry", query)
+	case 1:
+		return partial[0], nil
+	}
+	var names []string
+	for _, key := range partial {
+		names = append(names, p[key].Name)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("%q matches %s", query, strings.Join(names, ", "))
+}
+
+// findOrNew finds an item, or starts one keyed by the lower-case name.
+func (p pantry) findOrNew(query string) (string, pantryItem) {
+	if key, err := p.find(query); err == nil {
+		return key, p[key]
+	}
+	name := strings.TrimSpace(query)
+	return strings.ToLower(name), pantryItem{Name: name}
+}
+
+// stockFor finds the pantry item for a product by ID, else by name.
+func (p pantry) stockFor(id, name string) (pantryItem, bool) {
+	if item, ok := p[id]; ok && id != "" {
+		return item, true
+	}
+	if item, ok := p[strings.ToLower(name)]; ok {
+		return item, true
+	}
+	for _, item := range p {
+		if strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return pantryItem{}, false
+}
+
+type lowPantryItem struct {
+	Item  pantryItem
+	Packs int
+}
+
+// lowPantryItems are the items below their threshold with the packs needed
+// to get back to it.
+func lowPantryItems(items pantry) []lowPantryItem {
+	var low []lowPantryItem
+	for _, item := range items {
+		if !item.isLow() {
+			continue
+		}
+		packs, _ := packsFor(item.Threshold-item.Quantity, item.Unit, item.Pack)
+		low = append(low, lowPantryItem{Item: item, Packs: packs})
+	}
+	sort.Slice(low, func(i, j int) bool { return low[i].Item.Name < low[j].Item.Name })
+	return low
+}
+
+func showPantry(items pantry) {
+	if len(items) == 0 {
+		fmt.Println(msg("pantry.empty"))
+		return
+	}
+	var list []pantryItem
+	for _, item := range items {
+		list = append(list, item)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	fmt.Printf("%s\n\n", msg("pantry.header"))
+	for _, item := range list {
+		line := fmt.Sprintf("  %-40s %10s", item.Name, item.stockText())
+		if item.Threshold > 0 {
+			line += "  " + msg("pantry.min", ingredient{Quantity: item.Threshold, Unit: item.Unit}.amountText())
+		}
+		if item.isLow() {
+			line += "  ⚠"
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// paymentChoice is the payment option Picnic will charge: the preferred
+// option of the payment profile.
+type paymentChoice struct {
+	OptionID string `json:"option_id,omitempty"`
+	Method   string `json:"payment_method,omitempty"`
+	Name     string `json:"display_name,omitempty"`
+}
+
+// paymentResult is the machine-readable result of initiating a payment.
+type paymentResult struct {
+	OrderID       string        `json:"order_id"`
+	PaymentID     string        `json:"payment_id"`
+	TransactionID string        `json:"transaction_id"`
+	Method        paymentChoice `json:"method"`
+	NextAction    nextAction    `json:"next_action"`
+}
+
+// nextAction tells the caller what to do after initiating: open URL in a
+// browser ("redirect"), or just poll the checkout status ("poll_status").
+type nextAction struct {
+	Type string `json:"type"`
+	URL  string `json:"url,omitempty"`
+}
+
+func paymentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "payment",
+		Short: "Payment options",
+	}
+	cmd.AddCommand(paymentMethodsCmd())
+	return cmd
+}
+
+func paymentMethodsCmd() *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "methods",
+		Short: "List stored payment options and available payment methods",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			profile, err := client.GetPaymentProfile()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			if asJSON {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(profile)
+			}
+			showPaymentProfile(profile)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the payment profile as JSON")
+	return cmd
+}
+
+func showPaymentProfile(profile *picnic.PaymentProfile) {
+	fmt.Println(msg("payment.stored"))
+	if len(profile.StoredPaymentOptions) == 0 {
+		fmt.Println("  " + msg("payment.none"))
+	}
+	for _, option := range profile.StoredPaymentOptions {
+		marker := " "
+		if option.Id == profile.PreferredPaymentOptionId {
+			marker = "*"
+		}
+		name := option.DisplayName
+		if name == "" {
+			name = strings.TrimSpace(option.Brand + " " + option.Account)
+		}
+		fmt.Printf("%s %s  %s (%s)\n", marker, option.Id, name, option.PaymentMethod)
+	}
+
+	fmt.Println()
+	fmt.Println(msg("payment.available"))
+	displayNames := map[string]string{}
+	for _, method := range profile.PaymentMethods {
+		displayNames[method.PaymentMethod] = method.DisplayName
+	}
+	if len(profile.AvailablePaymentMethods) == 0 {
+		fmt.Println("  " + msg("payment.none"))
+	}
+	for _, method := range profile.AvailablePaymentMethods {
+		if name := displayNames[method.PaymentMethod]; name != "" {
+			fmt.Printf("  %s (%s)\n", method.PaymentMethod, name)
+		} else {
+			fmt.Printf("  %s\n", method.PaymentMethod)
+		}
+		for _, bank := range method.AvailableBanks {
+			fmt.Printf("      %s  %s\n", bank.BankId, bank.Name)
+		}
+	}
+	if profile.PreferredPaymentOptionId != "" {
+		fmt.Println()
+		fmt.Println(msg("payment.preferred", profile.PreferredPaymentOptionId))
+	}
+}
+
+// choosePayment returns the preferred payment option. Initiating a payment
+// takes only the order ID, so Picnic always charges the preferred option;
+// expected, a stored option ID or payment method, only checks that it is the
+// one that will be used; the API offers no way to choose another.
+func choosePayment(profile *picnic.PaymentProfile, expected string) (paymentChoice, error) {
+	expected = strings.TrimSpace(expected)
+	var choice paymentChoice
+	for _, option := range profile.StoredPaymentOptions {
+		if option.Id != profile.PreferredPaymentOptionId {
+			continue
+		}
+		name := option.DisplayName
+		if name == "" {
+			name = strings.TrimSpace(option.Brand + " " + option.Account)
+		}
+		choice = paymentChoice{OptionID: option.Id, Method: option.PaymentMethod, Name: name}
+	}
+	if expected == "" || expected == choice.OptionID || (choice.Method != "" && strings.EqualFold(expected, choice.Method)) {
+		return choice, nil
+	}
+	if choice.OptionID == "" {
+		return paymentChoice{}, fmt.Errorf("no preferred payment option, so the payment will not use %s; choose one in the Picnic app", expected)
+	}
+	return paymentChoice{}, fmt.Errorf("the payment uses the preferred option %s (%s), not %s; change it in the Picnic app", choice.OptionID, choice.Method, expected)
+}
+
+// resolvePaymentChoice fetches the payment profile and returns the option the
+// payment will use.
+func resolvePaymentChoice(client *picnic.Client, expected string) (paymentChoice, error) {
+	profile, err := client.GetPaymentProfile()
+	if err != nil {
+		invalidateAuthCache()
+		return paymentChoice{}, err
+	}
+	return choosePayment(profile, expected)
+}
+
+// initiatePayment starts the payment for an order with the preferred option.
+func initiatePayment(client *picnic.Client, orderID string, choice paymentChoice) (paymentResult, error) {
+	payment, err := client.InitiatePayment(orderID)
+	if err != nil {
+		return paymentResult{}, err
+	}
+	result := paymentResult{
+		OrderID:       orderID,
+		PaymentID:     payment.PaymentId,
+		TransactionID: payment.TransactionId,
+		Method:        choice,
+		NextAction:    nextAction{Type: "poll_status"},
+	}
+	switch {
+	case payment.Action.RedirectUrl != "":
+		result.NextAction = nextAction{Type: "redirect", URL: payment.Action.RedirectUrl}
+	case payment.IssuerAuthenticationUrl != "":
+		result.NextAction = nextAction{Type: "redirect", URL: payment.IssuerAuthenticationUrl}
+	}
+	return result, nil
+}
+
+// showPaymentResult prints the result, or writes it as JSON when asJSON is set.
+func showPaymentResult(result paymentResult, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	fmt.Println(msg("payment.initiated", result.TransactionID))
+	if result.Method.Method != "" {
+		method := result.Method.Method
+		if result.Method.Name != "" {
+			method += " (" + result.Method.Name + ")"
+		}
+		fmt.Println(msg("payment.method", method))
+	}
+	if result.NextAction.URL != "" {
+		fmt.Println(msg("payment.redirectURL", result.NextAction.URL))
+	}
+	return nil
+}
+
+func addPaymentFlags(cmd *cobra.Command, expectMethod *string) {
+	cmd.Flags().StringVar(expectMethod, "expect-method", "", "Fail unless the preferred payment option, which Picnic always charges, is this option id or payment method (see 'picnic payment methods')")
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// recipeCost is what the products for one recipe of a plan cost.
+type recipeCost struct {
+	Recipe string
+	Cost   int
+}
+
+func planCmd() *cobra.Command {
+	var servings int
+	var noPantry, yes bool
+	cmd := &cobra.Command{
+		Use:   "plan <file[@servings]>...",
+		Short: "Turn a week of recipes into one cart update",
+		Long: "Reads several recipes or shopping lists, in the formats of 'recipe add', and merges\n" +
+			"the ingredients that map to the same product. What the pantry (~/.picnic-pantry.json)\n" +
+			"has, with new completed deliveries added, is taken off before rounding up to whole\n" +
+			"packs. Append @N to a file to make N servings of it, or use --servings for all. After\n" +
+			"confirmation the products are added to the cart and the cost per recipe is shown at\n" +
+			"cart prices.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromStdin := false
+			var titles []string
+			var ingredients []ingredient
+			for _, arg := range args {
+				path, n, err := planArg(arg, servings)
+				if err != nil {
+					return err
+				}
+				fromStdin = fromStdin || path == "-"
+				r, err := readRecipe([]string{path})
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				factor, err := recipeScale(r, n, 0)
+				if err != nil {
+					return err
+				}
+				title := r.Name
+				if title == "" && path != "-" {
+					title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+				}
+				if title == "" {
+					title = recipeTitle(r)
+				}
+				titles = append(titles, title)
+				fmt.Println(msg("recipe.header", title, len(r.Ingredients)))
+				if factor != 1 {
+					fmt.Println("   " + msg("recipe.scaled", r.Yield, n))
+				}
+				for _, line := range r.Ingredients {
+					ing := parseIngredient(line).scaled(factor)
+					ing.Source = title
+					ingredients = append(ingredients, ing)
+				}
+			}
+			if err := noteFollowUp(); err != nil {
+				return err
+			}
+
+			proposals, unresolved := proposeCart(ingredients, func(name string) (productMatch, error) {
+				return findProductByName(name, searchArticlesRaw)
+			})
+			if !noPantry {
+				items, err := loadSyncedPantry()
+				if err != nil {
+					return err
+				}
+				applyPantry(proposals, items)
+			}
+			fmt.Println()
+			showCartProposal(proposals, unresolved)
+			toAdd := 0
+			for _, p := range proposals {
+				if p.Packs > 0 {
+					toAdd++
+				}
+			}
+			fmt.Println()
+			showRecipeCosts(msg("plan.estimated"), planCosts(titles, proposals, nil))
+			if toAdd == 0 {
+				if len(proposals) == 0 {
+					return fmt.Errorf("no products found for the plan")
+				}
+				fmt.Println(msg("plan.nothingToAdd"))
+				return nil
+			}
+
+			if !yes {
+				ok, err := confirmRecipe(msg("recipe.confirm", toAdd), fromStdin)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("plan not added")
+				}
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			cart, err := addProposalsToCart(client, proposals)
+			if err != nil {
+				return err
+			}
+			recordCartPrices(cart)
+			fmt.Println()
+			showRecipeCosts(msg("plan.atCartPrices"), planCosts(titles, proposals, cartPackPrices(cart)))
+			showCartSummary(cart)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&servings, "servings", 0, "Scale every recipe to this many servings")
+	cmd.Flags().BoolVar(&noPantry, "no-pantry", false, "Do not take the pantry stock off")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Add without asking")
+	return cmd
+}
+
+// planArg splits "file@servings"; without a suffix the servings default
+// applies.
+func planArg(arg string, servings int) (string, int, error) {
+	i := strings.LastIndex(arg, "@")
+	if i <= 0 {
+		return arg, servings, nil
+	}
+	n, err := strconv.Atoi(arg[i+1:])
+	if err != nil {
+		return arg, servings, nil
+	}
+	if n <= 0 {
+		return "", 0, fmt.Errorf("%s: servings must be positive", arg)
+	}
+	return arg[:i], n, nil
+}
+
+// applyPantry takes what the pantry has off each proposal.
+func applyPantry(proposals []cartProposal, items pantry) {
+	for i, p := range proposals {
+		item, ok := items.stockFor(p.Product.ID, p.Product.Name)
+		if !ok || item.Quantity <= 0 {
+			continue
+		}
+		proposals[i].Stock = []ingredient{{Name: item.Name, Quantity: item.Quantity, Unit: item.Unit}}
+		proposals[i].Packs, proposals[i].Exact = proposalPacks(p.Needs, p.Product.Unit, proposals[i].Stock)
+	}
+}
+
+// cartPackPrices maps the products in the cart to their price per pack. The
+// display price of a cart article is the total of its line.
+func cartPackPrices(cart *picnic.Order) map[string]int {
+	prices := map[string]int{}
+	if cart == nil {
+		return prices
+	}
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id != "" && article.DisplayPrice > 0 {
+				prices[article.Id] = article.DisplayPrice / max(article.Quantity(), 1)
+			}
+		}
+	}
+	return prices
+}
+
+// planCosts splits the cost of each product over the recipes that need it,
+// by the amount each needs when they use the same unit and evenly
+// otherwise. Prices come from prices by product ID, else from the match.
+func planCosts(titles []string, proposals []cartProposal, prices map[string]int) []recipeCost {
+	costs := map[string]int{}
+	for _, p := range proposals {
+		price := p.Product.Price
+		if cart, ok := prices[p.Product.ID]; ok {
+			price = cart
+		}
+		total := price * p.Packs
+		if total <= 0 || len(p.Needs) == 0 {
+			continue
+		}
+		weights := make([]float64, len(p.Needs))
+		sum := 0.0
+		for i, ing := range p.Needs {
+			weights[i] = ing.Quantity
+			if ing.Unit != p.Needs[0].Unit || ing.Unit == unitSmall || ing.Quantity <= 0 {
+				sum = 0
+				break
+			}
+			sum += ing.Quantity
+		}
+		if sum == 0 {
+			for i := range weights {
+				weights[i] = 1
+			}
+			sum = float64(len(weights))
+		}
+		left := total
+		for i, ing := range p.Needs {
+			share := left
+			if i < len(p.Needs)-1 {
+				share = int(float64(total)*weights[i]/sum + 0.5)
+				left -= share
+			}
+			costs[ing.Source] += share
+		}
+	}
+
+	var result []recipeCost
+	seen := map[string]bool{}
+	for _, title := range titles {
+		if seen[title] {
+			continue
+		}
+		seen[title] = true
+		result = append(result, recipeCost{Recipe: title, Cost: costs[title]})
+	}
+	return result
+}
+
+func showRecipeCosts(header string, costs []recipeCost) {
+	fmt.Println(header)
+	width := 0
+	for _, c := range costs {
+		width = max(width, len([]rune(c.Recipe)))
+	}
+	total := 0
+	for _, c := range costs {
+		total += c.Cost
+		fmt.Printf("  %s%s  %s\n", c.Recipe, strings.Repeat(" ", width-len([]rune(c.Recipe))), amount(c.Cost))
+	}
+	fmt.Println(msg("plan.total", amount(total)))
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestCartPackPrices(t *testing.T) {
+	store := newFakeStore()
+	store.cart["s1"] = 3
+	store.cart["s2"] = 1
+
+	got := cartPackPrices(store.order())
+	want := map[string]int{"s1": 129, "s2": 149}
+	if !maps.Equal(got, want) {
+		t.Errorf("cartPackPrices = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// priceObservation is a single sighting of a product price. Observations are
+// collected from past deliveries, search results and the cart so price changes
+// can be followed over time.
+type priceObservation struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Price  int    `json:"price"`
+	Unit   string `json:"unit"`
+	Date   string `json:"date"`
+	Source string `json:"source"`
+}
+
+const (
+	priceSourceDelivery = "delivery"
+	priceSourceSearch   = "search"
+	priceSourceCart     = "cart"
+)
+
+func priceHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "price-history <product_id|name>",
+		Short: "Show observed prices for a product",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := strings.Join(args, " ")
+			observations, err := loadPriceObservations()
+			if err != nil {
+				return err
+			}
+			byProduct := matchPriceObservations(observations, query)
+			if len(byProduct) == 0 {
+				fmt.Println(msg("prices.none", query))
+				return nil
+			}
+
+			ids := make([]string, 0, len(byProduct))
+			for id := range byProduct {
+				ids = append(ids, id)
+			}
+			sort.Slice(ids, func(i, j int) bool {
+				return byProduct[ids[i]][0].Name < byProduct[ids[j]][0].Name
+			})
+			for _, id := range ids {
+				showPriceHistory(byProduct[id])
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func reportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Reports based on local order and price history",
+	}
+	cmd.AddCommand(reportInflationCmd())
+	return cmd
+}
+
+func reportInflationCmd() *cobra.Command {
+	var months int
+	var minCount int
+	cmd := &cobra.Command{
+		Use:   "inflation",
+		Short: "Summarise price changes of staple products",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if months <= 0 {
+				return fmt.Errorf("invalid months: %d", months)
+			}
+			history, err := loadHistory()
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			observations, err := loadPriceObservations()
+			if err != nil {
+				return err
+			}
+			staples := stapleProductIDs(history, minCount)
+			if len(staples) == 0 {
+				fmt.Println(msg("prices.noStaples"))
+				return nil
+			}
+
+			since := time.Now().AddDate(0, -months, 0)
+			changes := inflationReport(observations, staples, since)
+			if len(changes) == 0 {
+				fmt.Println(msg("prices.notEnough", months))
+				return nil
+			}
+			showInflationReport(changes, months)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&months, "months", 6, "Number of months to look back")
+	cmd.Flags().IntVar(&minCount, "min-count", 3, "Minimum number of purchases for a product to count as a staple")
+	return cmd
+}
+
+type priceChange struct {
+	ID      string
+	Name    string
+	First   priceObservation
+	Last    priceObservation
+	Min     int
+	Max     int
+	Percent float64
+}
+
+func showPriceHistory(observations []priceObservation) {
+	first := observations[0]
+	fmt.Printf("%s\n\n", msg("prices.header", first.Name, first.ID))
+
+	min, max := first.Price, first.Price
+	previous := 0
+	for _, obs := range observations {
+		change := ""
+		if previous > 0 && obs.Price != previous {
+			change = "  " + formatPercent(percentChange(previous, obs.Price))
+		}
+		fmt.Printf("  %s  %-8s %-12s%s\n", formatObservationDate(obs.Date), amount(obs.Price), msg("prices.source."+obs.Source), change)
+		if obs.Price < min {
+			min = obs.Price
+		}
+		if obs.Price > max {
+			max = obs.Price
+		}
+		previous = obs.Price
+	}
+
+	last := observations[len(observations)-1]
+	fmt.Printf("\n  %s\n\n", msg("prices.summary", amount(min), amount(max), formatPercent(percentChange(first.Price, last.Price))))
+}
+
+func showInflationReport(changes []priceChange, months int) {
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println(msg("prices.inflationTitle", months))
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Println()
+
+	total := 0.0
+	increased := 0
+	for _, c := range changes {
+		fmt.Printf("%8s  %s\n", formatPercent(c.Percent), c.Name)
+		fmt.Printf("          %s\n", msg("prices.inflationLine",
+			amount(c.First.Price), amount(c.Last.Price), amount(c.Min), amount(c.Max), c.ID))
+		total += c.Percent
+		if c.Percent > 0 {
+			increased++
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 60))
+	fmt.Println(msg("prices.inflationSummary", increased, len(changes), formatPercent(total/float64(len(changes)))))
+}
+
+// inflationReport compares the first and last observed price within the window
+// for every staple, most expensive increase first.
+func inflationReport(observations []priceObservation, staples map[string]bool, since time.Time) []priceChange {
+	byProduct := map[string][]priceObservation{}
+	for _, obs := range observations {
+		if !staples[obs.ID] {
+			continue
+		}
+		t, ok := parseTimestamp(obs.Date)
+		if !ok || t.Before(since) {
+			continue
+		}
+		byProduct[obs.ID] = append(byProduct[obs.ID], obs)
+	}
+
+	var changes []priceChange
+	for id, list := range byProduct {
+		if len(list) < 2 || list[0].Price <= 0 {
+			continue
+		}
+		change := priceChange{
+			ID:    id,
+			Name:  list[len(list)-1].Name,
+			First: list[0],
+			Last:  list[len(list)-1],
+			Min:   list[0].Price,
+			Max:   list[0].Price,
+		}
+		for _, obs := range list {
+			if obs.Price < change.Min {
+				change.Min = obs.Price
+			}
+			if obs.Price > change.Max {
+				change.Max = obs.Price
+			}
+		}
+		change.Percent = percentChange(change.First.Price, change.Last.Price)
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Percent == changes[j].Percent {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].Percent > changes[j].Percent
+	})
+	return changes
+}
+
+// stapleProductIDs returns the products bought in at least minCount different
+// deliveries.
+func stapleProductIDs(history []productEntry, minCount int) map[string]bool {
+	dates := map[string]map[string]bool{}
+	for _, p := range history {
+		if dates[p.ID] == nil {
+			dates[p.ID] = map[string]bool{}
+		}
+		dates[p.ID][p.Date] = true
+	}
+	staples := map[string]bool{}
+	for id, seen := range dates {
+		if len(seen) >= minCount {
+			staples[id] = true
+		}
+	}
+	return staples
+}
+
+// matchPriceObservations groups observations by product, matching the query
+// against the product ID or, failing that, a case-insensitive name substring.
+func matchPriceObservations(observations []priceObservation, query string) map[string][]priceObservation {
+	query = strings.TrimSpace(query)
+	byProduct := map[string][]priceObservation{}
+	for _, obs := range observations {
+		if obs.ID == query {
+			byProduct[obs.ID] = append(byProduct[obs.ID], obs)
+		}
+	}
+	if len(byProduct) > 0 {
+		return byProduct
+	}
+	lower := strings.ToLower(query)
+	for _, obs := range observations {
+		if strings.Contains(strings.ToLower(obs.Name), lower) {
+			byProduct[obs.ID] = append(byProduct[obs.ID], obs)
+		}
+	}
+	return byProduct
+}
+
+// loadPriceObservations merges the recorded observations with the delivery
+// history written by analyze-orders, deduplicated and sorted by date.
+func loadPriceObservations() ([]priceObservation, error) {
+	var observations []priceObservation
+	path, err := pricesFilePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := readJSONFile(path, &observations); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	history, err := loadHistory()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	observations = append(observations, deliveryObservations(history)...)
+	observations = dedupeObservations(observations)
+	sort.SliceStable(observations, func(i, j int) bool {
+		ti, _ := parseTimestamp(observations[i].Date)
+		tj, _ := parseTimestamp(observations[j].Date)
+		return ti.Before(tj)
+	})
+	return observations, nil
+}
+
+// recordPrices appends observations to the local price file. Failures are
+// ignored so that recording never breaks the command that triggered it.
+func recordPrices(observations []priceObservation) {
+	if len(observations) == 0 {
+		return
+	}
+	path, err := pricesFilePath()
+	if err != nil {
+		return
+	}
+	var existing []priceObservation
+	if err := readJSONFile(path, &existing); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	_ = writeJSONFile(path, dedupeObservations(append(existing, observations...)))
+}
+
+func recordSearchPrices(results []searchResult) {
+	now := time.Now().Format(time.RFC3339)
+	observations := make([]priceObservation, 0, len(results))
+	for _, item := range results {
+		price := item.PriceIncludingPromotions()
+		if item.PriceMissing || price <= 0 {
+			continue
+		}
+		observations = append(observations, priceObservation{
+			ID:     item.Id,
+			Name:   item.Name,
+			Price:  price,
+			Unit:   item.UnitQuantity,
+			Date:   now,
+			Source: priceSourceSearch,
+		})
+	}
+	recordPrices(observations)
+}
+
+func recordCartPrices(cart *picnic.Order) {
+	if cart == nil {
+		return
+	}
+	now := time.Now().Format(time.RFC3339)
+	var observations []priceObservation
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			if article.Id == "" || article.Name == "" || article.DisplayPrice <= 0 {
+				continue
+			}
+			observations = append(observations, priceObservation{
+				ID:     article.Id,
+				Name:   article.Name,
+				Price:  article.DisplayPrice,
+				Unit:   article.UnitQuantity,
+				Date:   now,
+				Source: priceSourceCart,
+			})
+		}
+	}
+	recordPrices(observations)
+}
+
+// deliveryObservations converts history entries to observations. Delivery
+// prices are per order line, so they are divided by the quantity bought.
+func deliveryObservations(history []productEntry) []priceObservation {
+	observations := make([]priceObservation, 0, len(history))
+	for _, p := range history {
+		price := p.Price
+		if p.Quantity > 1 {
+			price = p.Price / p.Quantity
+		}
+		if price <= 0 {
+			continue
+		}
+		observations = append(observations, priceObservation{
+			ID:     p.ID,
+			Name:   p.Name,
+			Price:  price,
+			Unit:   p.Unit,
+			Date:   p.Date,
+			Source: priceSourceDelivery,
+		})
+	}
+	return observations
+}
+
+// dedupeObservations keeps one observation per product, price, source and day.
+func dedupeObservations(observations []priceObservation) []priceObservation {
+	seen := map[string]bool{}
+	out := make([]priceObservation, 0, len(observations))
+	for _, obs := range observations {
+		key := fmt.Sprintf("%s|%d|%s|%s", obs.ID, obs.Price, obs.Source, formatObservationDate(obs.Date))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, obs)
+	}
+	return out
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05.000-0700", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func formatObservationDate(value string) string {
+	if t, ok := parseTimestamp(value); ok {
+		return t.Format("2006-01-02")
+	}
+	return value
+}
+
+func percentChange(from, to int) float64 {
+	if from == 0 {
+		return 0
+	}
+	return float64(to-from) / float64(from) * 100
+}
+
+func formatPercent(value float64) string {
+	if math.Abs(value) < 0.05 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%+.1f%%", value)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// stdinReader is shared so that consecutive prompts do not lose input that a
+// previous reader already buffered.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// confirm asks a yes/no question and returns true only for an explicit yes.
+func confirm(question string) bool {
+	fmt.Printf("%s %s ", question, msg("prompt.yesNo"))
+	answer, _ := stdinReader.ReadString('\n')
+	return isYes(answer)
+}
+
+// confirmOnTerminal asks on the controlling terminal, for commands whose
+// stdin carries their input. Without a terminal it fails, so that nothing is
+// done unconfirmed and the command does not exit 0.
+func confirmOnTerminal(question string) (bool, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, fmt.Errorf("stdin is in use and there is no terminal to confirm on, run again with --yes")
+	}
+	defer tty.Close()
+	fmt.Fprintf(tty, "%s %s ", question, msg("prompt.yesNo"))
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	return isYes(answer), nil
+}
+
+func isYes(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "j", "ja", "o", "oui":
+		return true
+	}
+	return false
+}
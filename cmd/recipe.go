@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// cartProposal is a product to add for one or more ingredients.
+type cartProposal struct {
+	Product productMatch
+	Packs   int
+	Needs   []ingredient
+	// Stock is what the pantry has of the product, taken off the needs.
+	Stock []ingredient
+	// Exact is false when the pack size could not be compared with the
+	// amounts needed and one pack is proposed per ingredient unit.
+	Exact bool
+}
+
+type unresolvedIngredient struct {
+	Ingredient ingredient
+	Err        error
+}
+
+func recipeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipe",
+		Short: "Turn recipes into cart items",
+	}
+	cmd.AddCommand(recipeAddCmd())
+	return cmd
+}
+
+func recipeAddCmd() *cobra.Command {
+	var servings, yield int
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "add [file|-]",
+		Short: "Add the ingredients of a recipe to the cart",
+		Long: "Reads a recipe page or file with schema.org Recipe JSON-LD, or a plain list with one\n" +
+			"ingredient per line, from the file or from stdin (e.g. curl -s <url> | picnic recipe add).\n" +
+			"Each ingredient maps to the product you usually buy, learned by analyze-orders, or the\n" +
+			"first search result, rounded up to whole packs. The proposal is shown for confirmation,\n" +
+			"on the terminal when the recipe comes from stdin; without a terminal pass --yes.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromStdin := len(args) == 0 || args[0] == "-"
+			r, err := readRecipe(args)
+			if err != nil {
+				return err
+			}
+			factor, err := recipeScale(r, servings, yield)
+			if err != nil {
+				return err
+			}
+			if err := noteFollowUp(); err != nil {
+				return err
+			}
+
+			fmt.Println(msg("recipe.header", recipeTitle(r), len(r.Ingredients)))
+			if factor != 1 {
+				fmt.Println(msg("recipe.scaled", max(r.Yield, yield), servings))
+			}
+			var ingredients []ingredient
+			for _, line := range r.Ingredients {
+				ingredients = append(ingredients, parseIngredient(line).scaled(factor))
+			}
+			proposals, unresolved := proposeCart(ingredients, func(name string) (productMatch, error) {
+				return findProductByName(name, searchArticlesRaw)
+			})
+			fmt.Println()
+			showCartProposal(proposals, unresolved)
+			if len(proposals) == 0 {
+				return fmt.Errorf("no products found for the recipe")
+			}
+
+			if !yes {
+				ok, err := confirmRecipe(msg("recipe.confirm", len(proposals)), fromStdin)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("recipe not added")
+				}
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			cart, err := addProposalsToCart(client, proposals)
+			if err != nil {
+				return err
+			}
+			showCartSummary(cart)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&servings, "servings", 0, "Scale the recipe to this many servings")
+	cmd.Flags().IntVar(&yield, "yield", 0, "Servings the recipe makes, when it does not say")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Add without asking")
+	return cmd
+}
+
+func readRecipe(args []string) (recipe, error) {
+	var data []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(stdinReader)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return recipe{}, err
+	}
+	return parseRecipe(data)
+}
+
+// recipeScale is the factor to scale a recipe by for servings. yield
+// overrides the servings the recipe says it makes.
+func recipeScale(r recipe, servings, yield int) (float64, error) {
+	if servings < 0 || yield < 0 {
+		return 0, fmt.Errorf("servings and yield must be positive")
+	}
+	if servings == 0 {
+		return 1, nil
+	}
+	if yield == 0 {
+		yield = r.Yield
+	}
+	if yield == 0 {
+		return 0, fmt.Errorf("%s does not say how many servings it makes, pass --yield", recipeTitle(r))
+	}
+	return float64(servings) / float64(yield), nil
+}
+
+func recipeTitle(r recipe) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return msg("recipe.untitled")
+}
+
+// proposeCart maps ingredients to products and rounds the amounts needed per
+// product up to whole packs. Ingredients that map to the same product are
+// merged.
+func proposeCart(ingredients []ingredient, find func(string) (productMatch, error)) ([]cartProposal, []unresolvedIngredient) {
+	var proposals []cartProposal
+	var unresolved []unresolvedIngredient
+	found := map[string]productMatch{}
+	index := map[string]int{}
+	for _, ing := range ingredients {
+		if ing.Name == "" {
+			continue
+		}
+		key := strings.ToLower(ing.Name)
+		match, ok := found[key]
+		if !ok {
+			var err error
+			match, err = find(ing.Name)
+			if err != nil {
+				unresolved = append(unresolved, unresolvedIngredient{Ingredient: ing, Err: err})
+				continue
+			}
+			found[key] = match
+		}
+		i, ok := index[match.ID]
+		if !ok {
+			i = len(proposals)
+			index[match.ID] = i
+			proposals = append(proposals, cartProposal{Product: match})
+		}
+		proposals[i].Needs = append(proposals[i].Needs, ing)
+	}
+	for i := range proposals {
+		proposals[i].Packs, proposals[i].Exact = proposalPacks(proposals[i].Needs, proposals[i].Product.Unit, nil)
+	}
+	return proposals, unresolved
+}
+
+// proposalPacks adds up the amounts needed per unit, takes off the stock in
+// the same unit and rounds each rest up to whole packs. Stock counted in
+// packs is taken off the packs. Anything in stock covers what one pack
+// would.
+func proposalPacks(needs []ingredient, packUnit string, stock []ingredient) (int, bool) {
+	totals := map[string]float64{}
+	var units []string
+	for _, ing := range needs {
+		if _, ok := totals[ing.Unit]; !ok {
+			units = append(units, ing.Unit)
+		}
+		totals[ing.Unit] += ing.Quantity
+	}
+	have := map[string]float64{}
+	inStock := false
+	for _, s := range stock {
+		have[s.Unit] += s.Quantity
+		inStock = inStock || s.Quantity > 0
+	}
+
+	packs, exact := 0, true
+	for _, unit := range units {
+		if unit == unitSmall {
+			if !inStock {
+				packs++
+			}
+			continue
+		}
+		rest := totals[unit] - have[unit]
+		if unit != unitPack {
+			have[unit] = 0
+		}
+		if rest <= 1e-9 {
+			continue
+		}
+		n, ok := packsFor(rest, unit, packUnit)
+		packs += n
+		exact = exact && ok
+	}
+	if packStock := int(have[unitPack]); packStock > 0 {
+		packs = max(packs-packStock, 0)
+	}
+	return packs, exact
+}
+
+// showCartProposal prints the proposed products and returns their total.
+func showCartProposal(proposals []cartProposal, unresolved []unresolvedIngredient) int {
+	total := 0
+	for _, p := range proposals {
+		if p.Packs == 0 {
+			fmt.Printf("  %s\n", msg("recipe.inPantry", p.Product.Name))
+			continue
+		}
+		price := amount(p.Product.Price * p.Packs)
+		if p.Product.PriceMissing {
+			price = missingAmount()
+		} else {
+			total += p.Product.Price * p.Packs
+		}
+		line := fmt.Sprintf("  %dx %s", p.Packs, p.Product.Name)
+		if p.Product.Unit != "" {
+			line += " (" + p.Product.Unit + ")"
+		}
+		fmt.Printf("%s %s\n", line, price)
+		var needs []string
+		for _, ing := range p.Needs {
+			needs = append(needs, strings.TrimSpace(ing.amountText()+" "+ing.Name))
+		}
+		note := msg("recipe.for", strings.Join(needs, ", "))
+		if len(p.Stock) > 0 {
+			var stock []string
+			for _, ing := range p.Stock {
+				stock = append(stock, ing.amountText())
+			}
+			note += " " + msg("recipe.minusPantry", strings.Join(stock, ", "))
+		}
+		if !p.Exact {
+			note += " " + msg("recipe.packUnknown")
+		}
+		fmt.Printf("     %s\n", note)
+	}
+	if len(unresolved) > 0 {
+		fmt.Println()
+		fmt.Println(msg("recipe.unresolved"))
+		for _, u := range unresolved {
+			fmt.Printf("  - %s: %v\n", u.Ingredient.Line, u.Err)
+		}
+	}
+	fmt.Println()
+	fmt.Println(msg("recipe.total", amount(total)))
+	return total
+}
+
+func addProposalsToCart(client *picnic.Client, proposals []cartProposal) (*picnic.Order, error) {
+	var cart *picnic.Order
+	for _, p := range proposals {
+		if p.Packs == 0 {
+			continue
+		}
+		var err error
+		cart, err = client.AddToCart(p.Product.ID, p.Packs)
+		if err != nil {
+			invalidateAuthCache()
+			return nil, fmt.Errorf("adding %s: %w", p.Product.Name, err)
+		}
+		fmt.Println(msg("recipe.added", p.Packs, p.Product.Name))
+	}
+	return cart, nil
+}
+
+// confirmRecipe asks before adding to the cart, on the terminal when the
+// recipe came from stdin.
+func confirmRecipe(question string, fromStdin bool) (bool, error) {
+	if fromStdin {
+		return confirmOnTerminal(question)
+	}
+	return confirm(question), nil
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Units of an ingredient besides the base units of unitQuantity: a number of
+// packs such as "1 blik", or an amount one pack always covers, such as
+// "1 snufje zout".
+const (
+	unitPack  = "pack"
+	unitSmall = ""
+)
+
+var (
+	jsonLDPattern       = regexp.MustCompile(`(?is)<script[^>]*application/ld\+json[^>]*>(.*?)</script>`)
+	ingredientPattern   = regexp.MustCompile(`^(\d+\s+\d+/\d+|\d+/\d+|\d+(?:[.,]\d+)?)(?:\s*(?:-|–|tot|to|bis|à)\s*\d+(?:[.,]\d+)?)?\s*(.*)$`)
+	parenthesesPattern  = regexp.MustCompile(`\([^)]*\)`)
+	yieldNumberPattern  = regexp.MustCompile(`\d+`)
+	servingsLinePattern = regexp.MustCompile(`(?i)^(?:servings|serves|yield|porties|personen|portionen|personnes)\s*:\s*(\d+)`)
+)
+
+var unicodeFractions = strings.NewReplacer("½", " 1/2", "¼", " 1/4", "¾", " 3/4", "⅓", " 1/3", "⅔", " 2/3")
+
+// recipeMeasures are kitchen measures, in liters, next to the units of unitFactors.
+var recipeMeasures = map[string]float64{
+	"el": 0.015, "eetlepel": 0.015, "eetlepels": 0.015, "tbsp": 0.015, "tablespoon": 0.015, "tablespoons": 0.015,
+	"esslöffel": 0.015, "cs": 0.015,
+	"tl": 0.005, "theelepel": 0.005, "theelepels": 0.005, "tsp": 0.005, "teaspoon": 0.005, "teaspoons": 0.005,
+	"teelöffel": 0.005, "cc": 0.005,
+	"cup": 0.24, "cups": 0.24, "kopje": 0.24, "kopjes": 0.24, "tasse": 0.24, "tassen": 0.24,
+}
+
+// recipeCountWords are measures counted in packs or covered by one pack.
+var recipeCountWords = map[string]string{
+	"blik": unitPack, "blikken": unitPack, "blikje": unitPack, "blikjes": unitPack, "can": unitPack, "cans": unitPack,
+	"pak": unitPack, "pakken": unitPack, "pakje": unitPack, "pakjes": unitPack, "zak": unitPack, "zakje": unitPack,
+	"zakjes": unitPack, "bag": unitPack, "bags": unitPack, "pot": unitPack, "potje": unitPack, "jar": unitPack,
+	"jars": unitPack, "fles": unitPack, "flessen": unitPack, "bottle": unitPack, "bos": unitPack, "bosje": unitPack,
+	"bunch": unitPack, "doos": unitPack, "box": unitPack, "dose": unitPack, "dosen": unitPack, "packung": unitPack,
+	"boîte": unitPack, "sachet": unitPack, "bouquet": unitPack,
+	"snufje": unitSmall, "snuf": unitSmall, "pinch": unitSmall, "prise": unitSmall, "pincée": unitSmall,
+	"teen": unitSmall, "teentje": unitSmall, "teentjes": unitSmall, "tenen": unitSmall, "clove": unitSmall,
+	"cloves": unitSmall, "zehe": unitSmall, "zehen": unitSmall, "gousse": unitSmall, "gousses": unitSmall,
+	"takje": unitSmall, "takjes": unitSmall, "sprig": unitSmall, "sprigs": unitSmall, "scheutje": unitSmall,
+	"dash": unitSmall, "handvol": unitSmall, "handful": unitSmall, "blaadje": unitSmall, "blaadjes": unitSmall,
+}
+
+// recipe is a parsed recipe or shopping list.
+type recipe struct {
+	Name        string
+	Yield       int
+	Ingredients []string
+}
+
+// ingredient is a parsed ingredient line. Quantity is in Unit: kg, l or pcs,
+// a number of packs, or unitSmall when one pack covers it. Source is the
+// recipe it is for.
+type ingredient struct {
+	Line     string
+	Name     string
+	Quantity float64
+	Unit     string
+	Source   string
+}
+
+// parseRecipe reads a schema.org Recipe from JSON-LD, on its own or in an
+// HTML page, or else a plain list with one ingredient per line. In plain
+// lists a line starting with # names the recipe and "servings: 4" sets the
+// yield.
+func parseRecipe(data []byte) (recipe, error) {
+	trimmed := bytes.TrimSpace(data)
+	var blocks [][]byte
+	switch {
+	case len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '['):
+		blocks = [][]byte{trimmed}
+	default:
+		for _, m := range jsonLDPattern.FindAllSubmatch(data, -1) {
+			blocks = append(blocks, m[1])
+		}
+		if len(blocks) == 0 && bytes.Contains(bytes.ToLower(trimmed), []byte("<html")) {
+			return recipe{}, fmt.Errorf("no schema.org Recipe found in the page")
+		}
+	}
+	if len(blocks) > 0 {
+		for _, block := range blocks {
+			var value any
+			if err := json.Unmarshal(block, &value); err != nil {
+				continue
+			}
+			if node, ok := findRecipeNode(value); ok {
+				return recipeFromJSONLD(node)
+			}
+		}
+		return recipe{}, fmt.Errorf("no schema.org Recipe found")
+	}
+
+	var r recipe
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "-*•"))
+		switch {
+		case line == "" || strings.HasSuffix(line, ":"):
+		case strings.HasPrefix(line, "#"):
+			if r.Name == "" {
+				r.Name = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			}
+		case servingsLinePattern.MatchString(line):
+			r.Yield, _ = strconv.Atoi(servingsLinePattern.FindStringSubmatch(line)[1])
+		default:
+			r.Ingredients = append(r.Ingredients, line)
+		}
+	}
+	if len(r.Ingredients) == 0 {
+		return recipe{}, fmt.Errorf("no ingredients found")
+	}
+	return r, nil
+}
+
+// findRecipeNode finds the object with @type Recipe, also inside @graph and
+// lists.
+func findRecipeNode(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if node, ok := findRecipeNode(item); ok {
+				return node, true
+			}
+		}
+	case map[string]any:
+		types := []any{v["@type"]}
+		if list, ok := v["@type"].([]any); ok {
+			types = list
+		}
+		for _, t := range types {
+			if s, ok := t.(string); ok && strings.EqualFold(s, "Recipe") {
+				return v, true
+			}
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findRecipeNode(graph)
+		}
+	}
+	return nil, false
+}
+
+func recipeFromJSONLD(node map[string]any) (recipe, error) {
+	r := recipe{Yield: recipeYield(node["recipeYield"])}
+	if name, ok := node["name"].(string); ok {
+		r.Name = html.UnescapeString(strings.TrimSpace(name))
+	}
+	ingredients, _ := node["recipeIngredient"].([]any)
+	if ingredients == nil {
+		ingredients, _ = node["ingredients"].([]any)
+	}
+	for _, item := range ingredients {
+		if line, ok := item.(string); ok && strings.TrimSpace(line) != "" {
+			r.Ingredients = append(r.Ingredients, html.UnescapeString(strings.TrimSpace(line)))
+		}
+	}
+	if len(r.Ingredients) == 0 {
+		return recipe{}, fmt.Errorf("recipe %q lists no ingredients", r.Name)
+	}
+	return r, nil
+}
+
+// recipeYield reads recipeYield, which is a number, a text such as
+// "4 porties" or a list of those.
+func recipeYield(value any) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case string:
+		if m := yieldNumberPattern.FindString(v); m != "" {
+			n, _ := strconv.Atoi(m)
+			return n
+		}
+	case []any:
+		for _, item := range v {
+			if n := recipeYield(item); n > 0 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// parseIngredient reads lines such as "200 g bloem", "1½ el olijfolie",
+// "2 uien, gesnipperd" or "zout".
+func parseIngredient(line string) ingredient {
+	ing := ingredient{Line: line, Unit: unitSmall}
+	text := strings.TrimSpace(unicodeFractions.Replace(line))
+	m := ingredientPattern.FindStringSubmatch(text)
+	if m == nil {
+		ing.Name = cleanIngredientName(text)
+		return ing
+	}
+	quantity, ok := parseRecipeNumber(m[1])
+	if !ok {
+		ing.Name = cleanIngredientName(text)
+		return ing
+	}
+	rest := strings.TrimSpace(m[2])
+	ing.Quantity, ing.Unit = quantity, unitPiece
+
+	word, name, _ := strings.Cut(rest, " ")
+	unit := strings.TrimSuffix(strings.ToLower(word), ".")
+	if strings.TrimSpace(name) == "" {
+		unit = ""
+	}
+	if factor, ok := unitFactors[unit]; ok {
+		ing.Quantity, ing.Unit = quantity*factor.factor, factor.unit
+		rest = name
+	} else if liters, ok := recipeMeasures[unit]; ok {
+		ing.Quantity, ing.Unit = quantity*liters, unitLiter
+		rest = name
+	} else if kind, ok := recipeCountWords[unit]; ok {
+		ing.Unit = kind
+		rest = name
+	}
+	ing.Name = cleanIngredientName(rest)
+	return ing
+}
+
+func parseRecipeNumber(value string) (float64, bool) {
+	total := 0.0
+	for _, part := range strings.Fields(value) {
+		if num, den, ok := strings.Cut(part, "/"); ok {
+			n, errN := strconv.ParseFloat(num, 64)
+			d, errD := strconv.ParseFloat(den, 64)
+			if errN != nil || errD != nil || d == 0 {
+				return 0, false
+			}
+			total += n / d
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.Replace(part, ",", ".", 1), 64)
+		if err != nil {
+			return 0, false
+		}
+		total += n
+	}
+	return total, total > 0
+}
+
+// cleanIngredientName drops notes such as "(optioneel)" or ", gesnipperd"
+// that would spoil a product search.
+func cleanIngredientName(name string) string {
+	name = parenthesesPattern.ReplaceAllString(name, "")
+	name, _, _ = strings.Cut(name, ",")
+	name = strings.TrimSpace(name)
+	for _, prefix := range []string{"of ", "van ", "von ", "de "} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// scaled multiplies the quantity by factor; what one pack covers stays so.
+func (ing ingredient) scaled(factor float64) ingredient {
+	if ing.Unit != unitSmall {
+		ing.Quantity *= factor
+	}
+	return ing
+}
+
+// amountText shows the quantity, e.g. "250 g", "1.5 l" or "2x".
+func (ing ingredient) amountText() string {
+	format := func(v float64) string {
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	}
+	switch ing.Unit {
+	case unitKilogram:
+		if ing.Quantity < 1 {
+			return format(ing.Quantity*1000) + " g"
+		}
+		return format(ing.Quantity) + " kg"
+	case unitLiter:
+		if ing.Quantity < 1 {
+			return format(ing.Quantity*1000) + " ml"
+		}
+		return format(ing.Quantity) + " l"
+	case unitPiece, unitPack:
+		return format(ing.Quantity) + "x"
+	}
+	return ""
+}
+
+// packsFor is the number of packs of a product with the given unit quantity
+// needed for quantity of unit. ok is false when the pack size does not tell,
+// in which case one pack, or one per piece, is proposed.
+func packsFor(quantity float64, unit, packUnit string) (int, bool) {
+	pack, parsed := parseUnitQuantity(packUnit)
+	packs := func(amount float64) int {
+		return max(int(math.Ceil(quantity/amount-1e-9)), 1)
+	}
+	switch unit {
+	case unitSmall:
+		return 1, true
+	case unitPack:
+		return packs(1), true
+	case unitPiece:
+		if !parsed {
+			return packs(1), false
+		}
+		if pack.Unit == unitPiece {
+			return packs(pack.Amount), true
+		}
+		return 1, false
+	}
+	if parsed && pack.Unit == unit {
+		return packs(pack.Amount), true
+	}
+	return 1, false
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseIngredient(t *testing.T) {
+	tests := []struct {
+		line     string
+		name     string
+		quantity float64
+		unit     string
+	}{
+		// Fractions.
+		{"1½ el olijfolie", "olijfolie", 0.0225, unitLiter},
+		{"1/2 tl zout", "zout", 0.0025, unitLiter},
+		{"1 1/2 cup melk", "melk", 0.36, unitLiter},
+		{"¾ kg aardappelen", "aardappelen", 0.75, unitKilogram},
+		// Ranges take the lower bound.
+		{"2-3 uien, gesnipperd", "uien", 2, unitPiece},
+		{"2 tot 3 tomaten", "tomaten", 2, unitPiece},
+		{"1 – 2 cups flour", "flour", 0.24, unitLiter},
+		// Units and kitchen measures.
+		{"200 g bloem", "bloem", 0.2, unitKilogram},
+		{"1,5 l water", "water", 1.5, unitLiter},
+		{"2 EL Zucker", "Zucker", 0.03, unitLiter},
+		{"3 cs huile d'olive", "huile d'olive", 0.045, unitLiter},
+		// Count words.
+		{"1 blik tomatenblokjes", "tomatenblokjes", 1, unitPack},
+		{"2 pakjes roomboter", "roomboter", 2, unitPack},
+		{"2 teentjes knoflook", "knoflook", 2, unitSmall},
+		{"1 snufje peper", "peper", 1, unitSmall},
+		{"6 eieren", "eieren", 6, unitPiece},
+		// No quantity.
+		{"zout (optioneel)", "zout", 0, unitSmall},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := parseIngredient(tt.line)
+			if got.Name != tt.name || got.Unit != tt.unit || math.Abs(got.Quantity-tt.quantity) > 1e-9 {
+				t.Errorf("parseIngredient(%q) = %q %v %q, want %q %v %q", tt.line, got.Name, got.Quantity, got.Unit, tt.name, tt.quantity, tt.unit)
+			}
+		})
+	}
+}
+
+func TestParseRecipeJSONLD(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		title string
+		yield int
+	}{
+		{
+			"graph in a page",
+			`<html><head><script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+				{"@type": "WebPage", "name": "Recepten"},
+				{"@type": ["Recipe"], "name": "Pasta &amp; pesto", "recipeYield": ["4", "4 porties"],
+				 "recipeIngredient": ["200 g pasta", " ", "1 potje pesto"]}
+			]}</script></head></html>`,
+			"Pasta & pesto", 4,
+		},
+		{
+			"list of nodes",
+			`[{"@type": "Organization"}, {"@type": "Recipe", "name": "Soep", "recipeYield": 6, "recipeIngredient": ["1 l bouillon", "2 wortels"]}]`,
+			"Soep", 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parseRecipe([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Name != tt.title || r.Yield != tt.yield || len(r.Ingredients) != 2 {
+				t.Errorf("parseRecipe = %q, yield %d, %q", r.Name, r.Yield, r.Ingredients)
+			}
+		})
+	}
+}
+
+func TestRecipeYield(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  int
+	}{
+		{"number", 4.0, 4},
+		{"text", "4 porties", 4},
+		{"text with words first", "Serves 2-3", 2},
+		{"list", []any{"", "6 servings"}, 6},
+		{"list of numbers", []any{8.0, "8 stuks"}, 8},
+		{"no number", "een paar", 0},
+		{"missing", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recipeYield(tt.value); got != tt.want {
+				t.Errorf("recipeYield(%v) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPacksFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity float64
+		unit     string
+		packUnit string
+		packs    int
+		ok       bool
+	}{
+		{"weight", 0.6, unitKilogram, "500 gram", 2, true},
+		{"exact weight", 0.5, unitKilogram, "500 g", 1, true},
+		{"multipack", 0.9, unitLiter, "6 x 330 ml", 1, true},
+		{"pieces per pack", 8, unitPiece, "6 stuks", 2, true},
+		{"pieces, unknown pack", 3, unitPiece, "", 3, false},
+		{"pieces of a weighed pack", 2, unitPiece, "1 kg", 1, false},
+		{"other unit", 0.2, unitLiter, "500 g", 1, false},
+		{"packs", 2, unitPack, "400 g", 2, true},
+		{"small", 1, unitSmall, "", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packs, ok := packsFor(tt.quantity, tt.unit, tt.packUnit)
+			if packs != tt.packs || ok != tt.ok {
+				t.Errorf("packsFor(%v, %q, %q) = %d, %v, want %d, %v", tt.quantity, tt.unit, tt.packUnit, packs, ok, tt.packs, tt.ok)
+			}
+		})
+	}
+}
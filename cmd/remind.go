@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+const remindPollInterval = 5 * time.Minute
+
+var defaultReminderLeadTimes = []string{"2h", "30m"}
+
+type reminderConfig struct {
+	// LeadTimes are durations before the cut-off at which to notify.
+	LeadTimes []string `json:"lead_times,omitempty"`
+}
+
+// reminderState records which lead times already fired per slot, so that
+// repeated runs from cron notify only once.
+type reminderState map[string][]string
+
+func remindCmd() *cobra.Command {
+	var leadFlag string
+	var watch bool
+	var hooks notifyConfig
+	cmd := &cobra.Command{
+		Use:   "remind",
+		Short: "Remind about the cut-off time of the selected delivery slot",
+		Long: "Shows the time left until the cut-off of the selected slot and notifies once per lead time.\n" +
+			"Run it from cron, or with --watch as a long-lived process.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			leads := cfg.Reminders.LeadTimes
+			if leadFlag != "" {
+				leads = strings.Split(leadFlag, ",")
+			}
+			if len(leads) == 0 {
+				leads = defaultReminderLeadTimes
+			}
+			leadTimes, err := parseLeadTimes(leads)
+			if err != nil {
+				return err
+			}
+			if hooks.empty() {
+				hooks = cfg.Notify
+			}
+
+			for {
+				next, err := remindOnce(leadTimes, hooks, time.Now())
+				if !watch {
+					return err
+				}
+				if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+				time.Sleep(next)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&leadFlag, "at", "", "Comma separated lead times before the cut-off, e.g. 2h,30m (default reminders.lead_times from config, else 2h,30m)")
+	cmd.Flags().BoolVar(&watch, "watch", false, "Keep running and notify when each lead time is reached")
+	addNotifyFlags(cmd, &hooks)
+	return cmd
+}
+
+// remindOnce checks the selected slot, fires the hook when a lead time has
+// been reached, and returns how long to wait before checking again.
+func remindOnce(leadTimes []time.Duration, hooks notifyConfig, now time.Time) (time.Duration, error) {
+	client, err := getClient()
+	if err != nil {
+		return remindPollInterval, err
+	}
+	cart, err := client.GetCart()
+	if err != nil {
+		invalidateAuthCache()
+		return remindPollInterval, err
+	}
+	slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: cart.DeliverySlots, SelectedSlot: cart.SelectedSlot})
+	if !ok {
+		fmt.Println(msg("check.noSlot"))
+		return remindPollInterval, nil
+	}
+	cutOff, ok := parseTimestamp(slot.CutOffTime)
+	if !ok {
+		return remindPollInterval, fmt.Errorf("slot %s has no cut-off time", slot.SlotId)
+	}
+
+	remaining := cutOff.Sub(now)
+	if remaining <= 0 {
+		fmt.Println(msg("remind.passed", slot.WindowStart, slot.CutOffTime))
+		return remindPollInterval, nil
+	}
+	fmt.Println(msg("remind.remaining", formatRemaining(remaining), slot.WindowStart, slot.CutOffTime))
+
+	state := loadReminderState()
+	sent := map[string]bool{}
+	for _, lead := range state[slot.SlotId] {
+		sent[lead] = true
+	}
+
+	// Fire only the closest lead time reached; earlier ones crossed while
+	// nothing was running are marked as sent without a notification.
+	var fire time.Duration
+	fired := false
+	for _, lead := range leadTimes {
+		if remaining <= lead && !sent[lead.String()] {
+			fire = lead
+			fired = true
+			state[slot.SlotId] = append(state[slot.SlotId], lead.String())
+		}
+	}
+	if fired {
+		message := msg("remind.notify", formatRemaining(remaining), slot.WindowStart, cart.TotalCount, amount(cart.TotalPrice))
+		err := notify(hooks, "cut_off_reminder", message, map[string]string{
+			"slot_id":      slot.SlotId,
+			"window_start": slot.WindowStart,
+			"window_end":   slot.WindowEnd,
+			"cut_off_time": slot.CutOffTime,
+			"lead_time":    fire.String(),
+			"remaining":    strconv.Itoa(int(remaining.Minutes())),
+			"cart_items":   strconv.Itoa(cart.TotalCount),
+		})
+		saveReminderState(state)
+		if err != nil {
+			return remindPollInterval, err
+		}
+	}
+
+	next := remindPollInterval
+	for _, lead := range leadTimes {
+		if until := remaining - lead; until > 0 && until < next {
+			next = until
+		}
+	}
+	if remaining < next {
+		next = remaining
+	}
+	return next, nil
+}
+
+// parseLeadTimes parses the lead times, largest first.
+func parseLeadTimes(values []string) ([]time.Duration, error) {
+	leads := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid lead time %q", value)
+		}
+		leads = append(leads, d)
+	}
+	sort.Slice(leads, func(i, j int) bool { return leads[i] > leads[j] })
+	return leads, nil
+}
+
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours >= 24 {
+		return fmt.Sprintf("%dd%dh%02dm", hours/24, hours%24, minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", hours, minutes)
+}
+
+func loadReminderState() reminderState {
+	state := reminderState{}
+	path, err := remindersFilePath()
+	if err != nil {
+		return state
+	}
+	if err := readJSONFile(path, &state); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return reminderState{}
+	}
+	return state
+}
+
+func saveReminderState(state reminderState) {
+	path, err := remindersFilePath()
+	if err != nil {
+		return
+	}
+	_ = writeJSONFile(path, state)
+}
@@ -21,6 +21,9 @@ func removeCmd() *cobra.Command {
 				}
 				count = parsed
 			}
+			if err := noteFollowUp(); err != nil {
+				return err
+			}
 			client, err := getClient()
 			if err != nil {
 				return err
@@ -30,7 +33,7 @@ func removeCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Printf("\U0001F5D1 Removed %dx product %s from cart\n", count, args[0])
+			fmt.Println(msg("cart.removed", count, args[0]))
 			showCartSummary(cart)
 			return nil
 		},
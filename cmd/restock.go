@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"sort"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+// restockSuggestion is a staple that is predicted to run out before the
+// target delivery.
+type restockSuggestion struct {
+	ID           string
+	Name         string
+	Interval     float64
+	LastBought   time.Time
+	NextExpected time.Time
+	Quantity     int
+	Purchases    int
+}
+
+func suggestRestockCmd() *cobra.Command {
+	var minCount int
+	var add bool
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "suggest-restock",
+		Short: "Suggest staples that are due for the next delivery",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			history, err := loadHistory()
+			if errors.Is(err, fs.ErrNotExist) {
+				fmt.Println(msg("restock.noHistory"))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			target, err := restockTargetTime(client)
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			cart, err := client.GetCart()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+
+			suggestions := restockSuggestions(history, minCount, target, cartProductIDs(cart))
+			if len(suggestions) == 0 {
+				fmt.Println(msg("restock.none", target.Format("Mon 2006-01-02 15:04")))
+				return nil
+			}
+			showRestockSuggestions(suggestions, target)
+
+			if !add {
+				return nil
+			}
+			if !yes && !confirm(msg("restock.confirm", len(suggestions))) {
+				return nil
+			}
+			var last *picnic.Order
+			for _, s := range suggestions {
+				order, err := client.AddToCart(s.ID, s.Quantity)
+				if err != nil {
+					invalidateAuthCache()
+					return err
+				}
+				fmt.Println(msg("cart.added", s.Quantity, s.ID))
+				last = order
+			}
+			showCartSummary(last)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&minCount, "min-count", 3, "Minimum number of purchases for a product to be considered")
+	cmd.Flags().BoolVar(&add, "add", false, "Add the suggested products to the cart")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation when adding")
+	return cmd
+}
+
+// restockTargetTime is the start of the selected delivery slot, or of the
+// first available slot when none is selected.
+func restockTargetTime(client interface {
+	GetDeliverySlots() (*picnic.DeliverySlots, error)
+}) (time.Time, error) {
+	slots, err := client.GetDeliverySlots()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if slot, ok := selectedSlot(slots); ok {
+		if t, ok := parseTimestamp(slot.WindowStart); ok {
+			return t, nil
+		}
+	}
+	for _, slot := range slots.DeliverySlots {
+		if !slot.IsAvailable {
+			continue
+		}
+		if t, ok := parseTimestamp(slot.WindowStart); ok {
+			return t, nil
+		}
+	}
+	return time.Now(), nil
+}
+
+// restockSuggestions predicts, per product, the next purchase from the median
+// interval between past deliveries and returns those due by target.
+func restockSuggestions(history []productEntry, minCount int, target time.Time, inCart map[string]bool) []restockSuggestion {
+	type purchases struct {
+		name     string
+		dates    map[string]time.Time
+		quantity int
+	}
+	byProduct := map[string]*purchases{}
+	for _, p := range history {
+		t, ok := parseTimestamp(p.Date)
+		if !ok {
+			continue
+		}
+		entry, ok := byProduct[p.ID]
+		if !ok {
+			entry = &purchases{name: p.Name, dates: map[string]time.Time{}}
+			byProduct[p.ID] = entry
+		}
+		entry.dates[t.Format("2006-01-02")] = t
+		entry.quantity += p.Quantity
+	}
+
+	var suggestions []restockSuggestion
+	for id, entry := range byProduct {
+		if len(entry.dates) < minCount || inCart[id] {
+			continue
+		}
+		dates := make([]time.Time, 0, len(entry.dates))
+		for _, t := range entry.dates {
+			dates = append(dates, t)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+		gaps := make([]float64, 0, len(dates)-1)
+		for i := 1; i < len(dates); i++ {
+			gaps = append(gaps, dates[i].Sub(dates[i-1]).Hours()/24)
+		}
+		interval := median(gaps)
+		if interval <= 0 {
+			continue
+		}
+		last := dates[len(dates)-1]
+		next := last.Add(time.Duration(interval * 24 * float64(time.Hour)))
+		if next.After(target) {
+			continue
+		}
+		quantity := int(math.Round(float64(entry.quantity) / float64(len(dates))))
+		if quantity < 1 {
+			quantity = 1
+		}
+		suggestions = append(suggestions, restockSuggestion{
+			ID:           id,
+			Name:         entry.name,
+			Interval:     interval,
+			LastBought:   last,
+			NextExpected: next,
+			Quantity:     quantity,
+			Purchases:    len(dates),
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].NextExpected.Before(suggestions[j].NextExpected)
+	})
+	return suggestions
+}
+
+func showRestockSuggestions(suggestions []restockSuggestion, target time.Time) {
+	fmt.Printf("%s\n\n", msg("restock.header", target.Format("Mon 2006-01-02 15:04")))
+	now := time.Now()
+	for i, s := range suggestions {
+		fmt.Printf("%2d. %dx %s [%s]\n", i+1, s.Quantity, s.Name, s.ID)
+		fmt.Printf("    %s | %s\n", msg("restock.detail", s.Interval, s.LastBought.Format("2006-01-02")), restockDueText(s.NextExpected, now))
+	}
+	fmt.Println()
+}
+
+// restockDueText says how many days ago a product was due, or in how many
+// days it will be.
+func restockDueText(due, now time.Time) string {
+	days := int(math.Round(due.Sub(now).Hours() / 24))
+	switch {
+	case days > 0:
+		return msg("restock.dueIn", days)
+	case days < 0:
+		return msg("restock.overdue", -days)
+	}
+	return msg("restock.dueToday")
+}
+
+func cartProductIDs(cart *picnic.Order) map[string]bool {
+	ids := map[string]bool{}
+	if cart == nil {
+		return ids
+	}
+	for _, line := range cart.Items {
+		for _, article := range line.Items {
+			ids[article.Id] = true
+		}
+	}
+	return ids
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
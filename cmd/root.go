@@ -1,22 +1,32 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 )
 
-var rootCmd = &cobra.Command{
-	Use:          "picnic",
-	Short:        "Picnic CLI for managing your grocery cart",
-	SilenceUsage: true,
-}
+// version is the CLI version, kept in sync with flake.nix.
+const version = "0.1.0"
+
+func newRootCmd() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:          "picnic",
+		Short:        "Picnic CLI for managing your grocery cart",
+		SilenceUsage: true,
+	}
+	var lang string
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "Output language: en, nl, de or fr (default from LANG or PICNIC_COUNTRY)")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return setLanguage(lang)
+	}
 
-func Execute() {
 	rootCmd.AddCommand(searchCmd())
 	rootCmd.AddCommand(addCmd())
 	rootCmd.AddCommand(removeCmd())
+	rootCmd.AddCommand(buyCmd())
 	rootCmd.AddCommand(cartCmd())
 	rootCmd.AddCommand(clearCmd())
 	rootCmd.AddCommand(analyzeCmd())
@@ -24,9 +34,31 @@ func Execute() {
 	rootCmd.AddCommand(slotsCmd())
 	rootCmd.AddCommand(slotCmd())
 	rootCmd.AddCommand(checkoutCmd())
+	rootCmd.AddCommand(paymentCmd())
+	rootCmd.AddCommand(deliveryCmd())
+	rootCmd.AddCommand(orderCmd())
+	rootCmd.AddCommand(priceHistoryCmd())
+	rootCmd.AddCommand(reportCmd())
+	rootCmd.AddCommand(suggestRestockCmd())
+	rootCmd.AddCommand(calendarCmd())
+	rootCmd.AddCommand(remindCmd())
+	rootCmd.AddCommand(mcpCmd())
+	rootCmd.AddCommand(serveCmd())
+	rootCmd.AddCommand(tuiCmd())
+	rootCmd.AddCommand(recipeCmd())
+	rootCmd.AddCommand(planCmd())
+	rootCmd.AddCommand(pantryCmd())
+	rootCmd.AddCommand(shellCmd())
+	return rootCmd
+}
 
-	if err := rootCmd.Execute(); err != nil {
+func Execute() {
+	if err := newRootCmd().Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -19,19 +19,21 @@ func searchCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
+			recordSearchPrices(results)
 			if len(results) == 0 {
-				fmt.Printf("No products found for %q\n", query)
+				fmt.Println(msg("search.none", query))
 				return nil
 			}
 
-			fmt.Printf("\U0001F50D Search results for %q:\n\n", query)
+			fmt.Printf("%s\n\n", msg("search.header", query))
 			limit := 10
 			if len(results) < limit {
 				limit = len(results)
 			}
+			rememberSearchResults(results[:limit])
 			for i := 0; i < limit; i++ {
 				item := results[i]
-				price := formatPrice(item.PriceIncludingPromotions())
+				price := item.price()
 				unit := strings.TrimSpace(item.UnitQuantity)
 				fmt.Printf("%d. [%s] %s\n", i+1, item.Id, item.Name)
 				if unit != "" {
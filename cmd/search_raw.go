@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -18,26 +20,36 @@ type tokenMeta struct {
 
 const appVersion = "1.15.243-18832"
 
-func searchArticlesRaw(query string) ([]picnic.SingleArticle, error) {
-	ctx, err := getAuthContext()
-	if err != nil {
-		return nil, err
+// searchResult is a product from the search page. PriceMissing is set when
+// the page lists it without a price, so that it is not shown as free.
+type searchResult struct {
+	picnic.SingleArticle
+	PriceMissing bool
+}
+
+// price is the price including promotions, or missing.
+func (r searchResult) price() money {
+	if r.PriceMissing {
+		return missingAmount()
 	}
-	meta, err := parseTokenMeta(ctx.Token)
-	if err != nil {
-		return nil, err
+	return amount(r.PriceIncludingPromotions())
+}
+
+// clientSearchResults wraps the articles found by picnic.Client. The client
+// decodes a missing price as zero, so here a zero price counts as missing.
+func clientSearchResults(articles []picnic.SingleArticle) []searchResult {
+	results := make([]searchResult, 0, len(articles))
+	for _, article := range articles {
+		results = append(results, searchResult{SingleArticle: article, PriceMissing: article.PriceIncludingPromotions() == 0})
 	}
-	endpoint := fmt.Sprintf("https://storefront-prod.%s.picnicinternational.com/api/15/pages/search-page-results?search_term=%s",
-		strings.ToLower(ctx.Country), url.QueryEscape(query))
+	return results
+}
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+func searchArticlesRaw(query string) ([]searchResult, error) {
+	req, err := newRawRequest("GET", "/pages/search-page-results?search_term="+url.QueryEscape(query), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("x-picnic-auth", ctx.Token)
-	req.Header.Set("x-picnic-agent", fmt.Sprintf("%d;%s;", meta.PcClid, appVersion))
-	req.Header.Set("x-picnic-did", meta.PcDid)
-	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -56,11 +68,43 @@ func searchArticlesRaw(query string) ([]picnic.SingleArticle, error) {
 		return nil, err
 	}
 
-	var results []picnic.SingleArticle
+	var results []searchResult
 	extractSellingUnits(payload, &results)
 	return results, nil
 }
 
+// newRawRequest builds an authenticated storefront request for endpoints the
+// picnic-api library does not cover. body, when not nil, is sent as JSON.
+func newRawRequest(method, path string, body any) (*http.Request, error) {
+	ctx, err := getAuthContext()
+	if err != nil {
+		return nil, err
+	}
+	meta, err := parseTokenMeta(ctx.Token)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("https://storefront-prod.%s.picnicinternational.com/api/15%s", strings.ToLower(ctx.Country), path)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-picnic-auth", ctx.Token)
+	req.Header.Set("x-picnic-agent", fmt.Sprintf("%d;%s;", meta.PcClid, appVersion))
+	req.Header.Set("x-picnic-did", meta.PcDid)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
 func parseTokenMeta(token string) (tokenMeta, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
@@ -80,7 +124,7 @@ func parseTokenMeta(token string) (tokenMeta, error) {
 	return meta, nil
 }
 
-func extractSellingUnits(node interface{}, out *[]picnic.SingleArticle) {
+func extractSellingUnits(node interface{}, out *[]searchResult) {
 	switch v := node.(type) {
 	case map[string]interface{}:
 		if content, ok := v["content"].(map[string]interface{}); ok {
@@ -91,7 +135,7 @@ func extractSellingUnits(node interface{}, out *[]picnic.SingleArticle) {
 						var article picnic.SingleArticle
 						if err := json.Unmarshal(buf, &article); err == nil {
 							if strings.TrimSpace(article.Id) != "" {
-								*out = append(*out, article)
+								*out = append(*out, searchResult{SingleArticle: article, PriceMissing: !hasPrice(sellingUnit)})
 							}
 						}
 					}
@@ -107,3 +151,26 @@ func extractSellingUnits(node interface{}, out *[]picnic.SingleArticle) {
 		}
 	}
 }
+
+// hasPrice reports whether a raw selling unit has a display price of its own
+// or in a price decorator.
+func hasPrice(sellingUnit interface{}) bool {
+	unit, ok := sellingUnit.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok := unit["display_price"]; ok {
+		return true
+	}
+	decorators, _ := unit["decorators"].([]interface{})
+	for _, d := range decorators {
+		decorator, ok := d.(map[string]interface{})
+		if !ok || decorator["type"] != "PRICE" {
+			continue
+		}
+		if _, ok := decorator["display_price"]; ok {
+			return true
+		}
+	}
+	return false
+}
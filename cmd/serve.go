@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// restRoute is an endpoint of the REST server. Most endpoints run an MCP tool,
+// so both share handlers and schemas; the OpenAPI document is built from the
+// same routes.
+type restRoute struct {
+	Method  string
+	Path    string
+	Summary string
+	// Body takes the arguments from a JSON body instead of the query string.
+	Body    bool
+	ID      string
+	input   map[string]any
+	output  map[string]any
+	handler func(s *mcpServer, args json.RawMessage) (any, error)
+}
+
+// restServer serves the routes to clients that know the shared secret. The
+// Picnic client is created once and shared by all requests. Requests run one
+// at a time, as the handlers record prices and drop the cached login on
+// failures, which are not safe to run concurrently.
+type restServer struct {
+	mcp    *mcpServer
+	token  string
+	routes []restRoute
+
+	calls   sync.Mutex
+	mu      sync.Mutex
+	client  storefront
+	connect func() (storefront, error)
+}
+
+func serveCmd() *cobra.Command {
+	var addr string
+	var token string
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve cart, search, slots, deliveries and tracking as a local JSON API",
+		Long: "Runs an HTTP server with JSON endpoints for home automation and scripts. Requests must\n" +
+			"send 'Authorization: Bearer <token>' with the token from --token or PICNIC_SERVE_TOKEN.\n" +
+			"The OpenAPI document at /openapi.json lists the endpoints and needs no token.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if token == "" {
+				token = strings.TrimSpace(os.Getenv("PICNIC_SERVE_TOKEN"))
+			}
+			if token == "" {
+				return fmt.Errorf("a token is required, set PICNIC_SERVE_TOKEN or pass --token")
+			}
+			s := newRESTServer(token)
+			server := &http.Server{
+				Addr:              addr,
+				Handler:           s.handler(),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			fmt.Println(msg("serve.listening", "http://"+addr))
+			return server.ListenAndServe()
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8787", "Address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "Shared secret clients send as bearer token (default $PICNIC_SERVE_TOKEN)")
+	return cmd
+}
+
+func newRESTServer(token string) *restServer {
+	s := &restServer{
+		mcp:   newMCPServer(),
+		token: token,
+		connect: func() (storefront, error) {
+			return getClient()
+		},
+	}
+	s.mcp.connect = s.sharedClient
+	s.mcp.search = s.search
+	s.routes = restRoutes(s.mcp.tools)
+	return s
+}
+
+func restRoutes(tools []mcpTool) []restRoute {
+	fromTool := func(method, path, name string, body bool) restRoute {
+		for _, tool := range tools {
+			if tool.Name == name {
+				return restRoute{
+					Method:  method,
+					Path:    path,
+					Summary: tool.Description,
+					Body:    body,
+					ID:      tool.Name,
+					input:   tool.InputSchema,
+					output:  tool.OutputSchema,
+					handler: tool.handler,
+				}
+			}
+		}
+		panic("unknown tool " + name)
+	}
+	return []restRoute{
+		fromTool(http.MethodGet, "/cart", "view_cart", false),
+		fromTool(http.MethodPost, "/cart/items", "add_to_cart", true),
+		fromTool(http.MethodDelete, "/cart/items/{product_id}", "remove_from_cart", false),
+		fromTool(http.MethodGet, "/search", "search_products", false),
+		fromTool(http.MethodGet, "/products/{product_id}", "get_product", false),
+		fromTool(http.MethodGet, "/slots", "list_slots", false),
+		fromTool(http.MethodGet, "/deliveries", "list_deliveries", false),
+		{
+			Method:  http.MethodGet,
+			Path:    "/deliveries/{delivery_id}/tracking",
+			Summary: "Track a delivery on its way: arrival estimate, driver, vehicle and last known position.",
+			ID:      "track_delivery",
+			input: schemaObject(map[string]any{
+				"delivery_id": schemaString("Delivery ID"),
+			}, "delivery_id"),
+			output: schemaObject(map[string]any{
+				"delivery_id":    schemaString("Delivery ID"),
+				"in_progress":    schemaBoolean("Whether the delivery is on its way"),
+				"eta_start":      schemaString("Start of the expected arrival"),
+				"eta_end":        schemaString("End of the expected arrival"),
+				"query_interval": schemaInteger("Time between polls suggested by Picnic", 0),
+				"driver":         schemaString("Name of the driver"),
+				"vehicle":        schemaString("Name of the vehicle"),
+				"position": schemaObject(map[string]any{
+					"lat": schemaNumber("Latitude"),
+					"lng": schemaNumber("Longitude"),
+				}, "lat", "lng"),
+			}, "delivery_id", "in_progress"),
+			handler: trackDelivery,
+		},
+	}
+}
+
+type deliveryPosition struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type deliveryTracking struct {
+	DeliveryID    string            `json:"delivery_id"`
+	InProgress    bool              `json:"in_progress"`
+	EtaStart      string            `json:"eta_start,omitempty"`
+	EtaEnd        string            `json:"eta_end,omitempty"`
+	QueryInterval int               `json:"query_interval,omitempty"`
+	Driver        string            `json:"driver,omitempty"`
+	Vehicle       string            `json:"vehicle,omitempty"`
+	Position      *deliveryPosition `json:"position,omitempty"`
+}
+
+// trackDelivery combines the delivery position with its scenario, the route
+// Picnic expects the vehicle to drive, to find where the vehicle is now.
+func trackDelivery(s *mcpServer, raw json.RawMessage) (any, error) {
+	var args struct {
+		DeliveryID string `json:"delivery_id"`
+	}
+	if err := decodeToolArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	client, err := s.storefront()
+	if err != nil {
+		return nil, err
+	}
+	position, err := client.GetDeliveryPosition(args.DeliveryID)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	result := deliveryTracking{
+		DeliveryID:    args.DeliveryID,
+		InProgress:    position.ScenarioInProgress,
+		EtaStart:      position.EtaWindow.Start,
+		EtaEnd:        position.EtaWindow.End,
+		QueryInterval: position.QueryInterval,
+	}
+	if !position.ScenarioInProgress {
+		return result, nil
+	}
+	scenario, err := client.GetDeliveryScenario(args.DeliveryID)
+	if err != nil {
+		if isAuthError(err) {
+			invalidateAuthCache()
+		}
+		return nil, err
+	}
+	result.Driver = scenario.Driver.Name
+	result.Vehicle = scenario.Vehicle.Name
+	for _, point := range scenario.Scenario {
+		if point.TimeStamp > position.ScenarioTs {
+			break
+		}
+		result.Position = &deliveryPosition{Lat: point.Lat, Lng: point.Lng}
+	}
+	return result, nil
+}
+
+// sharedClient returns the Picnic client, creating it on first use and after
+// a failed request.
+func (s *restServer) sharedClient() (storefront, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		client, err := s.connect()
+		if err != nil {
+			return nil, err
+		}
+		s.client = client
+	}
+	return s.client, nil
+}
+
+// search searches with the shared client instead of logging in per request.
+func (s *restServer) search(query string) ([]searchResult, error) {
+	client, err := s.sharedClient()
+	if err != nil {
+		return nil, err
+	}
+	articles, err := client.SearchArticles(query)
+	if err != nil {
+		return nil, err
+	}
+	return clientSearchResults(articles), nil
+}
+
+func (s *restServer) resetClient() {
+	s.mu.Lock()
+	s.client = nil
+	s.mu.Unlock()
+}
+
+func (s *restServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.openAPI())
+	})
+	for _, route := range s.routes {
+		mux.Handle(route.Method+" "+route.Path, s.authorize(s.serveRoute(route)))
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSONError(w, http.StatusNotFound, "not found")
+	})
+	return mux
+}
+
+func (s *restServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="picnic"`)
+			writeJSONError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *restServer) serveRoute(route restRoute) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		args, err := route.arguments(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.calls.Lock()
+		result, err := route.handler(s.mcp, args)
+		s.calls.Unlock()
+		if err != nil {
+			var invalid *invalidArgumentError
+			if errors.As(err, &invalid) {
+				writeJSONError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if isAuthError(err) {
+				s.resetClient()
+			}
+			writeJSONError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// arguments collects the path, query and body values of a request into the
+// arguments of the route's handler, converted to the types of its schema.
+func (route restRoute) arguments(r *http.Request) (json.RawMessage, error) {
+	properties, _ := route.input["properties"].(map[string]any)
+	args := map[string]any{}
+	if route.Body {
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+				return nil, fmt.Errorf("invalid JSON body: %w", err)
+			}
+		}
+	} else {
+		query := r.URL.Query()
+		for name := range query {
+			property, ok := properties[name].(map[string]any)
+			if !ok || route.isPathParam(name) {
+				return nil, fmt.Errorf("unknown parameter %q", name)
+			}
+			value, err := parseParam(name, query.Get(name), property)
+			if err != nil {
+				return nil, err
+			}
+			args[name] = value
+		}
+	}
+	for _, name := range route.pathParams() {
+		args[name] = r.PathValue(name)
+	}
+	required, _ := route.input["required"].([]string)
+	for _, name := range required {
+		if _, ok := args[name]; !ok {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+	}
+	return json.Marshal(args)
+}
+
+func parseParam(name, value string, property map[string]any) (any, error) {
+	switch property["type"] {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if minimum, ok := property["minimum"].(int); err != nil || (ok && n < minimum) {
+			return nil, fmt.Errorf("invalid %s: %s", name, value)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", name, value)
+		}
+		return b, nil
+	}
+	if values, ok := property["enum"].([]string); ok {
+		for _, v := range values {
+			if strings.EqualFold(v, value) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid %s: %s, use one of %s", name, value, strings.Join(values, ", "))
+	}
+	return value, nil
+}
+
+func (route restRoute) pathParams() []string {
+	var names []string
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func (route restRoute) isPathParam(name string) bool {
+	for _, param := range route.pathParams() {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// openAPI describes the routes as an OpenAPI 3.1 document.
+func (s *restServer) openAPI() map[string]any {
+	errorResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content": map[string]any{"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/Error"},
+			}},
+		}
+	}
+	paths := map[string]any{}
+	for _, route := range s.routes {
+		properties, _ := route.input["properties"].(map[string]any)
+		required, _ := route.input["required"].([]string)
+		isRequired := func(name string) bool {
+			for _, r := range required {
+				if r == name {
+					return true
+				}
+			}
+			return false
+		}
+
+		var parameters []map[string]any
+		for _, name := range route.pathParams() {
+			parameters = append(parameters, openAPIParameter(name, "path", true, properties[name]))
+		}
+		operation := map[string]any{
+			"operationId": route.ID,
+			"summary":     route.Summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     map[string]any{"application/json": map[string]any{"schema": route.output}},
+				},
+				"400": errorResponse("Invalid request"),
+				"401": errorResponse("Missing or invalid bearer token"),
+				"502": errorResponse("Picnic request failed"),
+			},
+		}
+		if route.Body {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": route.input}},
+			}
+		} else {
+			names := make([]string, 0, len(properties))
+			for name := range properties {
+				if !route.isPathParam(name) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				parameters = append(parameters, openAPIParameter(name, "query", isRequired(name), properties[name]))
+			}
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+
+		item, _ := paths[route.Path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   "Picnic CLI API",
+			"version": version,
+		},
+		"paths":    paths,
+		"security": []map[string]any{{"bearer": []string{}}},
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+			"schemas": map[string]any{
+				"Error": schemaObject(map[string]any{"error": schemaString("What went wrong")}, "error"),
+			},
+		},
+	}
+}
+
+func openAPIParameter(name, in string, required bool, schema any) map[string]any {
+	parameter := map[string]any{
+		"name":     name,
+		"in":       in,
+		"required": required,
+		"schema":   schema,
+	}
+	if property, ok := schema.(map[string]any); ok {
+		if description, ok := property["description"]; ok {
+			parameter["description"] = description
+		}
+	}
+	return parameter
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// maxShellHistory bounds the number of commands kept in the history file.
+const maxShellHistory = 1000
+
+var resultRefPattern = regexp.MustCompile(`^#(\d+)$`)
+
+// errShellExit ends the shell.
+var errShellExit = errors.New("exit")
+
+// shellResults are the products listed by the last search in the shell, which
+// #1, #2, ... refer to.
+var shellResults []searchResult
+
+// shellBuiltins are completed next to the commands; help is cobra's own.
+var shellBuiltins = []string{"exit", "help", "history", "results"}
+
+func shellCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Run commands in one logged-in session with history and tab completion",
+		Long: "Starts an interactive shell that runs the picnic commands without the 'picnic'\n" +
+			"prefix and logs in once. #N refers to the Nth product of the last search, e.g.\n" +
+			"'search melk' followed by 'add #3 2'. Tab completes commands, flags and #N.\n" +
+			"Builtins: results, history, exit. Commands are read from stdin when it is not a\n" +
+			"terminal, e.g. picnic shell < script.txt, stopping at the first failing command.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keepSessionClient = true
+			defer func() { keepSessionClient = false }()
+
+			if _, err := stty("-g"); err != nil {
+				return runShellScript(stdinReader)
+			}
+			return runShellInteractive()
+		},
+	}
+	return cmd
+}
+
+func runShellScript(in *bufio.Reader) error {
+	for {
+		line, err := in.ReadString('\n')
+		if line != "" {
+			if runErr := runShellLine(line); runErr != nil {
+				if errors.Is(runErr, errShellExit) {
+					return nil
+				}
+				return fmt.Errorf("%s: %w", strings.TrimSpace(line), runErr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func runShellInteractive() error {
+	history := loadShellHistory()
+	editor := &lineEditor{in: stdinReader, history: history, complete: shellComplete}
+	fmt.Println(msg("shell.welcome"))
+	for {
+		line, err := editor.readLine("picnic> ")
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		editor.history = append(editor.history, line)
+		saveShellHistory(editor.history)
+
+		if err := runShellLine(line); err != nil {
+			if errors.Is(err, errShellExit) {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// runShellLine runs a command line with a fresh command tree, so that flags
+// do not carry over from the previous command.
+func runShellLine(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "# ") || line == "#" {
+		return nil
+	}
+	args, err := splitShellWords(line)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return errShellExit
+	case "results":
+		showShellResults()
+		return nil
+	case "history":
+		for i, entry := range loadShellHistory() {
+			fmt.Printf("%4d  %s\n", i+1, entry)
+		}
+		return nil
+	case "shell":
+		return fmt.Errorf("already in the shell")
+	}
+	args, err = expandResultRefs(args)
+	if err != nil {
+		return err
+	}
+	if !hasLangFlag(args) {
+		args = append([]string{"--lang", currentLanguage}, args...)
+	}
+
+	root := newRootCmd()
+	root.SilenceErrors = true
+	root.SetArgs(args)
+	err = root.Execute()
+	if isAuthError(err) {
+		forgetLogin()
+	}
+	return err
+}
+
+// rememberSearchResults keeps the listed results of a search for #N.
+func rememberSearchResults(results []searchResult) {
+	shellResults = append([]searchResult{}, results...)
+}
+
+func showShellResults() {
+	if len(shellResults) == 0 {
+		fmt.Println(msg("shell.noResults"))
+		return
+	}
+	for i, item := range shellResults {
+		fmt.Printf("#%d [%s] %s %s\n", i+1, item.Id, item.Name, item.price())
+	}
+}
+
+// expandResultRefs replaces #N by the ID of the Nth product of the last search.
+func expandResultRefs(args []string) ([]string, error) {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = arg
+		m := resultRefPattern.FindStringSubmatch(arg)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 || n > len(shellResults) {
+			return nil, fmt.Errorf("no search result %s, run 'search <query>' first", arg)
+		}
+		expanded[i] = shellResults[n-1].Id
+	}
+	return expanded, nil
+}
+
+func hasLangFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--lang" || strings.HasPrefix(arg, "--lang=") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitShellWords splits a line into words, honouring single and double
+// quotes and backslash escapes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellComplete completes the last word of before: commands and subcommands,
+// flags, and #N references or IDs of the last search results. It returns
+// the completed word and, when several fit, the candidates to show.
+func shellComplete(before string) (string, []string) {
+	fields := strings.Fields(before)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(before, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	var values, labels []string
+	add := func(value, label string) {
+		if strings.HasPrefix(value, word) {
+			values = append(values, value)
+			labels = append(labels, label)
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(word, "#"):
+		if m := resultRefPattern.FindStringSubmatch(word); m != nil {
+			if n, _ := strconv.Atoi(m[1]); n >= 1 && n <= len(shellResults) {
+				return shellResults[n-1].Id + " ", nil
+			}
+		}
+		for i, item := range shellResults {
+			ref := fmt.Sprintf("#%d", i+1)
+			add(ref, fmt.Sprintf("%s [%s] %s", ref, item.Id, item.Name))
+		}
+	case len(fields) == 0:
+		for _, c := range newRootCmd().Commands() {
+			if !c.Hidden && c.Name() != "shell" && c.Name() != "completion" {
+				add(c.Name(), c.Name())
+			}
+		}
+		for _, name := range shellBuiltins {
+			add(name, name)
+		}
+	default:
+		found, rest, err := newRootCmd().Find(fields)
+		if err != nil {
+			break
+		}
+		switch {
+		case strings.HasPrefix(word, "-"):
+			found.Flags().VisitAll(func(f *pflag.Flag) {
+				add("--"+f.Name, "--"+f.Name)
+			})
+		case len(rest) == 0 && found.HasSubCommands():
+			for _, c := range found.Commands() {
+				if !c.Hidden {
+					add(c.Name(), c.Name())
+				}
+			}
+		default:
+			for i, item := range shellResults {
+				add(item.Id, fmt.Sprintf("#%d [%s] %s", i+1, item.Id, item.Name))
+			}
+		}
+	}
+
+	switch len(values) {
+	case 0:
+		return word, nil
+	case 1:
+		return values[0] + " ", nil
+	}
+	sort.Strings(labels)
+	return commonPrefix(values), labels
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func loadShellHistory() []string {
+	path, err := shellHistoryFilePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var history []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			history = append(history, line)
+		}
+	}
+	return history
+}
+
+func saveShellHistory(history []string) {
+	path, err := shellHistoryFilePath()
+	if err != nil {
+		return
+	}
+	if len(history) > maxShellHistory {
+		history = history[len(history)-maxShellHistory:]
+	}
+	_ = os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0o600)
+}
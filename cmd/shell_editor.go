@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// lineEditor reads a line from the terminal in raw mode with cursor keys,
+// history and tab completion. The terminal is only raw while reading, so
+// commands can prompt as usual.
+type lineEditor struct {
+	in       *bufio.Reader
+	history  []string
+	complete func(before string) (string, []string)
+}
+
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	restore, err := rawTerminal()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	var line []rune
+	pos := 0
+	historyIndex := len(e.history)
+	redraw := func() {
+		fmt.Print("\r\x1b[K" + prompt + string(line))
+		if back := len(line) - pos; back > 0 {
+			fmt.Printf("\x1b[%dD", back)
+		}
+	}
+	redraw()
+
+	for {
+		key, err := readKey(e.in)
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case "enter":
+			fmt.Print("\r\n")
+			return string(line), nil
+		case "ctrl+c":
+			fmt.Print("^C\r\n")
+			line, pos = nil, 0
+		case "\x04":
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+		case "backspace":
+			if pos > 0 {
+				line = append(line[:pos-1], line[pos:]...)
+				pos--
+			}
+		case "left":
+			pos = max(pos-1, 0)
+		case "right":
+			pos = min(pos+1, len(line))
+		case "\x01":
+			pos = 0
+		case "\x05":
+			pos = len(line)
+		case "up", "down":
+			if key == "up" && historyIndex > 0 {
+				historyIndex--
+			} else if key == "down" && historyIndex < len(e.history) {
+				historyIndex++
+			} else {
+				continue
+			}
+			line = nil
+			if historyIndex < len(e.history) {
+				line = []rune(e.history[historyIndex])
+			}
+			pos = len(line)
+		case "tab":
+			before := string(line[:pos])
+			word, candidates := e.complete(before)
+			start := strings.LastIndexAny(before, " \t") + 1
+			completed := []rune(before[:start] + word)
+			line = append(completed, line[pos:]...)
+			pos = len(completed)
+			if len(candidates) > 0 {
+				fmt.Print("\r\n" + strings.Join(candidates, "\r\n") + "\r\n")
+			}
+		default:
+			r := []rune(key)
+			if len(r) != 1 || !unicode.IsPrint(r[0]) {
+				continue
+			}
+			line = append(line[:pos], append(r, line[pos:]...)...)
+			pos++
+		}
+		redraw()
+	}
+}
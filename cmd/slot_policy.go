@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	policyEarliest        = "earliest"
+	policyLatest          = "latest"
+	policyPreferredWindow = "preferred-window"
+	policyCheapestMinimum = "cheapest-minimum"
+)
+
+var slotPolicies = []string{policyEarliest, policyLatest, policyPreferredWindow, policyCheapestMinimum}
+
+// slotWindow is a recurring delivery window, e.g. weekdays after 18:00.
+// Empty fields do not restrict the window.
+type slotWindow struct {
+	Days   []string `json:"days,omitempty"`
+	After  string   `json:"after,omitempty"`
+	Before string   `json:"before,omitempty"`
+}
+
+func (w slotWindow) validate() error {
+	if _, err := parseWeekdays(w.Days); err != nil {
+		return err
+	}
+	for _, clock := range []string{w.After, w.Before} {
+		if clock == "" {
+			continue
+		}
+		if _, err := parseClock(clock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches reports whether a slot starts on one of the days, not before After,
+// and ends no later than Before, in the slot's own time zone.
+func (w slotWindow) matches(start, end time.Time) bool {
+	days, err := parseWeekdays(w.Days)
+	if err != nil {
+		return false
+	}
+	if len(days) > 0 && !days[start.Weekday()] {
+		return false
+	}
+	if w.After != "" {
+		after, _ := parseClock(w.After)
+		if minuteOfDay(start) < after {
+			return false
+		}
+	}
+	if w.Before != "" {
+		before, _ := parseClock(w.Before)
+		endMinute := minuteOfDay(end)
+		if !sameDay(start, end) {
+			endMinute += 24 * 60
+		}
+		if endMinute > before {
+			return false
+		}
+	}
+	return true
+}
+
+func (w slotWindow) String() string {
+	parts := []string{}
+	if len(w.Days) > 0 {
+		parts = append(parts, strings.Join(w.Days, ","))
+	}
+	if w.After != "" || w.Before != "" {
+		parts = append(parts, w.After+"-"+w.Before)
+	}
+	return strings.Join(parts, " ")
+}
+
+// slotFilter narrows the slot list by the --day, --after, --before and
+// --available flags.
+type slotFilter struct {
+	day       string
+	window    slotWindow
+	available bool
+}
+
+func (f slotFilter) validate() error {
+	if f.day != "" {
+		if _, _, err := parseDayFilter(f.day, time.Now()); err != nil {
+			return err
+		}
+	}
+	return f.window.validate()
+}
+
+func (f slotFilter) matches(slot picnic.DeliverySlot) bool {
+	if f.available && !slot.IsAvailable {
+		return false
+	}
+	start, ok := parseTimestamp(slot.WindowStart)
+	if !ok {
+		return f.day == "" && f.window.After == "" && f.window.Before == ""
+	}
+	end, ok := parseTimestamp(slot.WindowEnd)
+	if !ok {
+		end = start
+	}
+	if f.day != "" {
+		date, weekday, _ := parseDayFilter(f.day, time.Now())
+		if date != "" && start.Format("2006-01-02") != date {
+			return false
+		}
+		if date == "" && start.Weekday() != weekday {
+			return false
+		}
+	}
+	return f.window.matches(start, end)
+}
+
+func (f slotFilter) apply(slots []picnic.DeliverySlot) []picnic.DeliverySlot {
+	var out []picnic.DeliverySlot
+	for _, slot := range slots {
+		if f.matches(slot) {
+			out = append(out, slot)
+		}
+	}
+	return out
+}
+
+func addSlotFilterFlags(cmd *cobra.Command, filter *slotFilter) {
+	cmd.Flags().StringVar(&filter.day, "day", "", "Only slots on this day (mon..sun, today, tomorrow or YYYY-MM-DD)")
+	cmd.Flags().StringVar(&filter.window.After, "after", "", "Only slots starting at or after HH:MM")
+	cmd.Flags().StringVar(&filter.window.Before, "before", "", "Only slots ending at or before HH:MM")
+	cmd.Flags().BoolVar(&filter.available, "available", false, "Only available slots")
+}
+
+func slotPickCmd() *cobra.Command {
+	var policy string
+	var dryRun bool
+	filter := slotFilter{}
+	cmd := &cobra.Command{
+		Use:   "pick",
+		Short: "Choose and select a delivery slot by policy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if policy == "" {
+				policy = cfg.Slots.Policy
+			}
+			if policy == "" {
+				policy = policyEarliest
+			}
+			if err := filter.validate(); err != nil {
+				return err
+			}
+			filter.available = true
+
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			slots, err := client.GetDeliverySlots()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			slot, err := pickSlot(filter.apply(slots.DeliverySlots), policy, cfg.Slots.Preferred)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(msg("slots.picked", policy))
+			showSlot(slot)
+			if dryRun {
+				return nil
+			}
+			order, err := client.SetDeliverySlot(slot.SlotId)
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			fmt.Println(msg("slots.set", slot.SlotId))
+			showCartSummary(order)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&policy, "policy", "", "Selection policy: "+strings.Join(slotPolicies, ", ")+" (default from config, else earliest)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the chosen slot without selecting it")
+	addSlotFilterFlags(cmd, &filter)
+	return cmd
+}
+
+// pickSlot chooses one of the available slots according to policy.
+func pickSlot(slots []picnic.DeliverySlot, policy string, preferred []slotWindow) (picnic.DeliverySlot, error) {
+	candidates := make([]picnic.DeliverySlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.IsAvailable {
+			candidates = append(candidates, slot)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ti, _ := parseTimestamp(candidates[i].WindowStart)
+		tj, _ := parseTimestamp(candidates[j].WindowStart)
+		return ti.Before(tj)
+	})
+
+	switch policy {
+	case policyEarliest:
+	case policyLatest:
+		for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		}
+	case policyPreferredWindow:
+		if len(preferred) == 0 {
+			return picnic.DeliverySlot{}, fmt.Errorf("policy %s needs slots.preferred in the config file", policy)
+		}
+		var matching []picnic.DeliverySlot
+		for _, slot := range candidates {
+			start, ok := parseTimestamp(slot.WindowStart)
+			if !ok {
+				continue
+			}
+			end, ok := parseTimestamp(slot.WindowEnd)
+			if !ok {
+				end = start
+			}
+			for _, window := range preferred {
+				if window.matches(start, end) {
+					matching = append(matching, slot)
+					break
+				}
+			}
+		}
+		candidates = matching
+	case policyCheapestMinimum:
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].MinimumOrderValue < candidates[j].MinimumOrderValue
+		})
+	default:
+		return picnic.DeliverySlot{}, fmt.Errorf("unknown policy %q (supported: %s)", policy, strings.Join(slotPolicies, ", "))
+	}
+
+	if len(candidates) == 0 {
+		return picnic.DeliverySlot{}, fmt.Errorf("no available slot matches policy %s", policy)
+	}
+	return candidates[0], nil
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseWeekdays accepts day names plus the shorthands "weekdays" and "weekend".
+func parseWeekdays(names []string) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "weekdays":
+			for d := time.Monday; d <= time.Friday; d++ {
+				days[d] = true
+			}
+			continue
+		case "weekend":
+			days[time.Saturday] = true
+			days[time.Sunday] = true
+			continue
+		}
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid day %q", name)
+		}
+		days[day] = true
+	}
+	return days, nil
+}
+
+// parseDayFilter returns either a date (YYYY-MM-DD) or a weekday for --day.
+func parseDayFilter(value string, now time.Time) (string, time.Weekday, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch value {
+	case "today":
+		return now.Format("2006-01-02"), 0, nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), 0, nil
+	}
+	if day, ok := weekdayNames[value]; ok {
+		return "", day, nil
+	}
+	if _, err := time.Parse("2006-01-02", value); err == nil {
+		return value, 0, nil
+	}
+	return "", 0, fmt.Errorf("invalid day %q", value)
+}
+
+// parseClock parses HH:MM into minutes since midnight. "24:00" is allowed as
+// the end of the day.
+func parseClock(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || hours < 0 || minutes < 0 || minutes > 59 || hours*60+minutes > 24*60 {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return hours*60 + minutes, nil
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
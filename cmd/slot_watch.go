@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	picnic "github.com/simonmartyr/picnic-api"
+	"github.com/spf13/cobra"
+)
+
+func slotWatchCmd() *cobra.Command {
+	var windowFlag string
+	var selectSlot bool
+	var interval time.Duration
+	var maxInterval time.Duration
+	var timeout time.Duration
+	var hooks notifyConfig
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Wait for a delivery slot in a window to become available",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseSlotWindow(windowFlag)
+			if err != nil {
+				return err
+			}
+			if interval <= 0 {
+				return fmt.Errorf("invalid interval: %s", interval)
+			}
+			if maxInterval < interval {
+				maxInterval = interval
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if hooks.empty() {
+				hooks = cfg.Notify
+			}
+
+			var deadline time.Time
+			if timeout > 0 {
+				deadline = time.Now().Add(timeout)
+			}
+			fmt.Println(msg("watch.started", window, interval))
+
+			wait := interval
+			for {
+				slot, found, err := findWatchedSlot(window)
+				switch {
+				case err != nil:
+					if isAuthError(err) {
+						invalidateAuthCache()
+					}
+					wait *= 2
+					if wait > maxInterval {
+						wait = maxInterval
+					}
+					fmt.Fprintln(os.Stderr, msg("watch.error", err, wait))
+				case found:
+					return slotWatchFound(slot, selectSlot, hooks)
+				default:
+					wait = interval
+				}
+
+				sleep := wait + time.Duration(rand.Int63n(int64(wait/10)+1))
+				if !deadline.IsZero() {
+					// Sleep until the deadline at most, so the last poll
+					// happens right before giving up.
+					remaining := time.Until(deadline)
+					if remaining <= 0 {
+						return fmt.Errorf("no slot in %s became available within %s", window, timeout)
+					}
+					sleep = min(sleep, remaining)
+				}
+				time.Sleep(sleep)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&windowFlag, "window", "", `Window to watch, e.g. "Fri 18:00-20:00" or "weekdays 18:00-22:00"`)
+	cmd.Flags().BoolVar(&selectSlot, "select", false, "Select the slot as soon as it becomes available")
+	cmd.Flags().DurationVar(&interval, "interval", time.Minute, "Time between polls")
+	cmd.Flags().DurationVar(&maxInterval, "max-interval", 15*time.Minute, "Maximum time between polls when requests fail")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Give up after this long (default: run until a slot is found)")
+	addNotifyFlags(cmd, &hooks)
+	_ = cmd.MarkFlagRequired("window")
+	return cmd
+}
+
+// findWatchedSlot returns the earliest available slot inside the window.
+func findWatchedSlot(window slotWindow) (picnic.DeliverySlot, bool, error) {
+	client, err := getClient()
+	if err != nil {
+		return picnic.DeliverySlot{}, false, err
+	}
+	slots, err := client.GetDeliverySlots()
+	if err != nil {
+		return picnic.DeliverySlot{}, false, err
+	}
+	filter := slotFilter{window: window, available: true}
+	slot, err := pickSlot(filter.apply(slots.DeliverySlots), policyEarliest, nil)
+	if err != nil {
+		return picnic.DeliverySlot{}, false, nil
+	}
+	return slot, true, nil
+}
+
+func slotWatchFound(slot picnic.DeliverySlot, selectSlot bool, hooks notifyConfig) error {
+	fmt.Println(msg("watch.found"))
+	showSlot(slot)
+
+	event := "slot_available"
+	message := msg("watch.notifyAvailable", slot.WindowStart, slot.WindowEnd)
+	if selectSlot {
+		client, err := getClient()
+		if err != nil {
+			return err
+		}
+		order, err := client.SetDeliverySlot(slot.SlotId)
+		if err != nil {
+			invalidateAuthCache()
+			return err
+		}
+		fmt.Println(msg("slots.set", slot.SlotId))
+		showCartSummary(order)
+		event = "slot_selected"
+		message = msg("watch.notifySelected", slot.WindowStart, slot.WindowEnd)
+	}
+
+	return notify(hooks, event, message, map[string]string{
+		"slot_id":      slot.SlotId,
+		"window_start": slot.WindowStart,
+		"window_end":   slot.WindowEnd,
+	})
+}
+
+// parseSlotWindow parses "Fri 18:00-20:00", "weekdays 18:00-22:00",
+// "sat,sun" or "18:00-20:00" into a slotWindow.
+func parseSlotWindow(value string) (slotWindow, error) {
+	var window slotWindow
+	for _, field := range strings.Fields(value) {
+		if strings.Contains(field, ":") {
+			parts := strings.SplitN(field, "-", 2)
+			window.After = parts[0]
+			if len(parts) == 2 {
+				window.Before = parts[1]
+			}
+			continue
+		}
+		window.Days = append(window.Days, strings.Split(field, ",")...)
+	}
+	if len(window.Days) == 0 && window.After == "" && window.Before == "" {
+		return window, fmt.Errorf("invalid window %q", value)
+	}
+	if err := window.validate(); err != nil {
+		return window, err
+	}
+	return window, nil
+}
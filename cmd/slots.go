@@ -4,14 +4,19 @@ import (
 	"fmt"
 	"strings"
 
+	picnic "github.com/simonmartyr/picnic-api"
 	"github.com/spf13/cobra"
 )
 
 func slotsCmd() *cobra.Command {
+	filter := slotFilter{}
 	cmd := &cobra.Command{
 		Use:   "slots",
 		Short: "List available delivery slots",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := filter.validate(); err != nil {
+				return err
+			}
 			client, err := getClient()
 			if err != nil {
 				return err
@@ -21,43 +26,58 @@ func slotsCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			if slots == nil || len(slots.DeliverySlots) == 0 {
-				fmt.Println("No delivery slots found")
+			if slots == nil {
+				fmt.Println(msg("slots.none"))
+				return nil
+			}
+			matching := filter.apply(slots.DeliverySlots)
+			if len(matching) == 0 {
+				fmt.Println(msg("slots.none"))
 				return nil
 			}
 
-			fmt.Println("Delivery slots:\n")
-			for _, slot := range slots.DeliverySlots {
-				status := "unavailable"
-				if slot.IsAvailable {
-					status = "available"
-				}
-				selected := ""
-				if slot.Selected {
-					selected = " (selected)"
-				}
-				window := strings.TrimSpace(slot.WindowStart + " - " + slot.WindowEnd)
-				fmt.Printf("- %s%s\n  id: %s | %s\n", window, selected, slot.SlotId, status)
-				if slot.MinimumOrderValue > 0 {
-					fmt.Printf("  minimum order: %s\n", formatPrice(slot.MinimumOrderValue))
-				}
-				if !slot.IsAvailable && strings.TrimSpace(slot.UnavailabilityReason) != "" {
-					fmt.Printf("  reason: %s\n", slot.UnavailabilityReason)
-				}
+			fmt.Printf("%s\n\n", msg("slots.header"))
+			for _, slot := range matching {
+				showSlot(slot)
 				fmt.Println()
 			}
 			return nil
 		},
 	}
+	addSlotFilterFlags(cmd, &filter)
 	return cmd
 }
 
+func showSlot(slot picnic.DeliverySlot) {
+	status := msg("slots.unavailable")
+	if slot.IsAvailable {
+		status = msg("slots.available")
+	}
+	selected := ""
+	if slot.Selected {
+		selected = msg("slots.selected")
+	}
+	window := strings.TrimSpace(slot.WindowStart + " - " + slot.WindowEnd)
+	fmt.Printf("- %s%s\n  id: %s | %s\n", window, selected, slot.SlotId, status)
+	if strings.TrimSpace(slot.CutOffTime) != "" {
+		fmt.Printf("  %s\n", msg("slots.cutOff", slot.CutOffTime))
+	}
+	if slot.MinimumOrderValue > 0 {
+		fmt.Printf("  %s\n", msg("slots.minimum", amount(slot.MinimumOrderValue)))
+	}
+	if !slot.IsAvailable && strings.TrimSpace(slot.UnavailabilityReason) != "" {
+		fmt.Printf("  %s\n", msg("slots.reason", slot.UnavailabilityReason))
+	}
+}
+
 func slotCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "slot",
 		Short: "Manage delivery slots",
 	}
 	cmd.AddCommand(slotSetCmd())
+	cmd.AddCommand(slotPickCmd())
+	cmd.AddCommand(slotWatchCmd())
 	return cmd
 }
 
@@ -76,10 +96,23 @@ func slotSetCmd() *cobra.Command {
 				invalidateAuthCache()
 				return err
 			}
-			fmt.Printf("Selected slot %s\n", args[0])
+			fmt.Println(msg("slots.set", args[0]))
 			showCartSummary(order)
 			return nil
 		},
 	}
 	return cmd
 }
+
+// selectedSlot returns the slot currently selected for the cart.
+func selectedSlot(slots *picnic.DeliverySlots) (picnic.DeliverySlot, bool) {
+	if slots == nil {
+		return picnic.DeliverySlot{}, false
+	}
+	for _, slot := range slots.DeliverySlots {
+		if slot.Selected || (slots.SelectedSlot.SlotId != "" && slot.SlotId == slots.SelectedSlot.SlotId) {
+			return slot, true
+		}
+	}
+	return picnic.DeliverySlot{}, false
+}
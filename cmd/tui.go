@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+)
+
+func tuiCmd() *cobra.Command {
+	var headless bool
+	var width, height int
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Search, fill the cart, pick a slot and check out in a full-screen interface",
+		Long: "Opens a full-screen interface in the search box. / searches again, tab switches\n" +
+			"between results and cart, enter or + adds the selected product, - removes one,\n" +
+			"s picks a delivery slot, c checks out and q quits. Checkout leaves the screen to\n" +
+			"pay like 'checkout run'. With --headless, keys are read from stdin and the final\n" +
+			"screen is printed, e.g. printf 'melk\\n+q' | picnic tui --headless",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClient()
+			if err != nil {
+				return err
+			}
+			m := newTUIModel(client, searchArticlesRaw)
+			if headless {
+				err = runHeadlessTUI(m, stdinReader, os.Stdout, width, height)
+			} else {
+				err = runTUI(m)
+			}
+			if err != nil || !m.checkout {
+				return err
+			}
+
+			cart, err := client.GetCart()
+			if err != nil {
+				invalidateAuthCache()
+				return err
+			}
+			if err := validateCheckoutCart(cart); err != nil {
+				return err
+			}
+			if err := enforceGuardrails(client, cart); err != nil {
+				return err
+			}
+			choice, err := resolvePaymentChoice(client, "")
+			if err != nil {
+				return err
+			}
+			return placeOrder(client, cart, checkoutOptions{payment: choice, interval: 5 * time.Second, timeout: 15 * time.Minute})
+		},
+	}
+	cmd.Flags().BoolVar(&headless, "headless", false, "Read keys from stdin and print the final screen instead of using the terminal")
+	cmd.Flags().IntVar(&width, "width", 100, "Screen width for --headless")
+	cmd.Flags().IntVar(&height, "height", 30, "Screen height for --headless")
+	return cmd
+}
+
+// runTUI draws the model full-screen until it quits. The terminal is put in
+// raw mode with stty so that no terminal library is needed.
+func runTUI(m *tuiModel) error {
+	restore, err := rawTerminal()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	keys := stdinReader
+	for !m.quit {
+		height, width := terminalSize()
+		fmt.Print("\x1b[H" + strings.Join(m.render(width, height), "\r\n"))
+		key, err := readKey(keys)
+		if err != nil {
+			return err
+		}
+		m.handleKey(key)
+	}
+	return nil
+}
+
+// runHeadlessTUI applies the keys in in to the model and writes the screen
+// after the last one, for scripts and for checking the layout.
+func runHeadlessTUI(m *tuiModel, in io.Reader, out io.Writer, width, height int) error {
+	keys := bufio.NewReader(in)
+	for !m.quit {
+		key, err := readKey(keys)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		m.handleKey(key)
+	}
+	for _, line := range m.render(width, height) {
+		if _, err := fmt.Fprintln(out, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readKey reads one key press: a character, or a name such as "enter",
+// "up" or "ctrl+c" for control keys and escape sequences.
+func readKey(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3:
+		return "ctrl+c", nil
+	case '\t':
+		return "tab", nil
+	case '\r', '\n':
+		return "enter", nil
+	case 8, 127:
+		return "backspace", nil
+	case 27:
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		if next, _ := r.Peek(1); next[0] != '[' && next[0] != 'O' {
+			return "esc", nil
+		}
+		seq, err := r.Peek(2)
+		if err != nil {
+			return "esc", nil
+		}
+		name, ok := map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left", 'Z': "shift+tab"}[seq[1]]
+		if !ok {
+			return "esc", nil
+		}
+		_, _ = r.Discard(2)
+		return name, nil
+	}
+	if b < utf8.RuneSelf {
+		return string(rune(b)), nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return "", err
+	}
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	return string(ch), nil
+}
+
+func rawTerminal() (func(), error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal, use --headless: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		_, _ = stty(strings.TrimSpace(state))
+	}, nil
+}
+
+// terminalSize returns the rows and columns of the terminal, or 24x80.
+func terminalSize() (int, int) {
+	out, err := stty("size")
+	if err == nil {
+		fields := strings.Fields(out)
+		if len(fields) == 2 {
+			rows, errRows := strconv.Atoi(fields[0])
+			cols, errCols := strconv.Atoi(fields[1])
+			if errRows == nil && errCols == nil && rows > 0 && cols > 0 {
+				return rows, cols
+			}
+		}
+	}
+	return 24, 80
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
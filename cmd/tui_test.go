@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHeadlessTUIScript(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := newFakeStore()
+	m := newTUIModel(store, store.search)
+
+	// Search, add the first result twice, take one off, pick the second slot
+	// and confirm the checkout.
+	var out bytes.Buffer
+	if err := runHeadlessTUI(m, strings.NewReader("melk\n++-sj\ncy"), &out, 100, 20); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCalls := []string{"GetCart", "AddToCart s1 1", "AddToCart s1 1", "RemoveFromCart s1 1", "SetDeliverySlot slot2"}
+	if !slices.Equal(store.calls, wantCalls) {
+		t.Errorf("calls = %q, want %q", store.calls, wantCalls)
+	}
+	if !m.checkout {
+		t.Error("checkout was not confirmed")
+	}
+
+	frame := out.String()
+	lines := strings.Split(strings.TrimRight(frame, "\n"), "\n")
+	if len(lines) != 20 {
+		t.Errorf("frame has %d lines, want 20", len(lines))
+	}
+	slot := formatSlotWindow(store.slots()[1])
+	for _, want := range []string{
+		msg("tui.header", 1, amount(129), slot),
+		msg("tui.search") + ": melk",
+		"1. Halfvolle melk",
+		"2. Karnemelk",
+		"1x Halfvolle melk",
+		msg("tui.confirmCheckout", 1, amount(129), slot),
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame lacks %q:\n%s", want, frame)
+		}
+	}
+}
+
+func TestHeadlessTUICheckoutDeclined(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := newFakeStore()
+	store.cart["s2"] = 1
+	store.slot = "slot1"
+	m := newTUIModel(store, store.search)
+
+	var out bytes.Buffer
+	if err := runHeadlessTUI(m, strings.NewReader("\tcn"), &out, 80, 12); err != nil {
+		t.Fatal(err)
+	}
+	if m.checkout {
+		t.Error("checkout confirmed with n")
+	}
+	if !strings.Contains(out.String(), msg("tui.checkoutAborted")) {
+		t.Errorf("frame lacks the aborted status:\n%s", out.String())
+	}
+}
@@ -0,0 +1,455 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	picnic "github.com/simonmartyr/picnic-api"
+)
+
+// Panes of the terminal UI that take the keyboard.
+type tuiPane int
+
+const (
+	paneSearch tuiPane = iota
+	paneResults
+	paneCart
+	paneSlots
+)
+
+// tuiStore is the part of picnic.Client the terminal UI uses.
+type tuiStore interface {
+	GetCart() (*picnic.Order, error)
+	AddToCart(itemId string, count int) (*picnic.Order, error)
+	RemoveFromCart(itemId string, count int) (*picnic.Order, error)
+	GetDeliverySlots() (*picnic.DeliverySlots, error)
+	SetDeliverySlot(slotId string) (*picnic.Order, error)
+}
+
+// tuiModel is the state of the terminal UI. It does not touch the terminal:
+// keys go in through handleKey and frames come out of render, so the same
+// model drives the full-screen and the headless mode.
+type tuiModel struct {
+	store  tuiStore
+	search func(query string) ([]searchResult, error)
+
+	focus       tuiPane
+	query       string
+	results     []searchResult
+	searched    string
+	resultIndex int
+	cart        *picnic.Order
+	cartIndex   int
+	slots       []picnic.DeliverySlot
+	slotIndex   int
+	confirming  bool
+	status      string
+
+	quit     bool
+	checkout bool
+}
+
+func newTUIModel(store tuiStore, search func(string) ([]searchResult, error)) *tuiModel {
+	m := &tuiModel{store: store, search: search, focus: paneSearch, cart: &picnic.Order{}}
+	if cart, err := store.GetCart(); err != nil {
+		m.fail(err)
+	} else {
+		m.cart = cart
+	}
+	return m
+}
+
+// handleKey applies a key as decoded by readKey.
+func (m *tuiModel) handleKey(key string) {
+	if key == "ctrl+c" {
+		m.quit = true
+		return
+	}
+	if m.confirming {
+		m.confirming = false
+		if key == "y" || key == "Y" || key == "j" || key == "o" {
+			m.checkout = true
+			m.quit = true
+			return
+		}
+		m.status = msg("tui.checkoutAborted")
+		return
+	}
+
+	switch m.focus {
+	case paneSearch:
+		m.handleSearchKey(key)
+	case paneSlots:
+		m.handleSlotKey(key)
+	default:
+		m.handleListKey(key)
+	}
+}
+
+func (m *tuiModel) handleSearchKey(key string) {
+	switch key {
+	case "enter":
+		m.runSearch()
+	case "backspace":
+		if r := []rune(m.query); len(r) > 0 {
+			m.query = string(r[:len(r)-1])
+		}
+	case "esc", "tab", "down":
+		m.focus = paneResults
+	default:
+		if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+			m.query += key
+		}
+	}
+}
+
+func (m *tuiModel) handleListKey(key string) {
+	switch key {
+	case "q":
+		m.quit = true
+	case "/":
+		m.focus = paneSearch
+	case "tab", "left", "right":
+		if m.focus == paneResults {
+			m.focus = paneCart
+		} else {
+			m.focus = paneResults
+		}
+	case "up", "k":
+		m.move(-1)
+	case "down", "j":
+		m.move(1)
+	case "enter", "+", "=":
+		if id, name, ok := m.selectedProduct(); ok {
+			m.changeCart(m.store.AddToCart, id, msg("tui.added", name))
+		}
+	case "-":
+		if id, name, ok := m.selectedProduct(); ok {
+			m.changeCart(m.store.RemoveFromCart, id, msg("tui.removed", name))
+		}
+	case "r":
+		if cart, err := m.store.GetCart(); err != nil {
+			m.fail(err)
+		} else {
+			m.cart = cart
+			m.status = ""
+		}
+	case "s":
+		m.openSlots()
+	case "c":
+		m.askCheckout()
+	}
+}
+
+func (m *tuiModel) handleSlotKey(key string) {
+	switch key {
+	case "up", "k":
+		m.slotIndex = clampIndex(m.slotIndex-1, len(m.slots))
+	case "down", "j":
+		m.slotIndex = clampIndex(m.slotIndex+1, len(m.slots))
+	case "enter":
+		if m.slotIndex >= len(m.slots) {
+			return
+		}
+		slot := m.slots[m.slotIndex]
+		if !slot.IsAvailable {
+			m.status = msg("tui.slotUnavailable")
+			return
+		}
+		cart, err := m.store.SetDeliverySlot(slot.SlotId)
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		m.cart = cart
+		m.status = msg("tui.slotSelected", formatSlotWindow(slot))
+		m.focus = paneCart
+	case "esc", "tab", "s", "q":
+		m.focus = paneCart
+	}
+}
+
+func (m *tuiModel) runSearch() {
+	query := strings.TrimSpace(m.query)
+	if query == "" {
+		return
+	}
+	results, err := m.search(query)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	recordSearchPrices(results)
+	m.results = results
+	m.searched = query
+	m.resultIndex = 0
+	m.status = ""
+	m.focus = paneResults
+}
+
+func (m *tuiModel) move(delta int) {
+	if m.focus == paneResults {
+		m.resultIndex = clampIndex(m.resultIndex+delta, len(m.results))
+	} else {
+		m.cartIndex = clampIndex(m.cartIndex+delta, len(newMCPCart(m.cart).Items))
+	}
+}
+
+// selectedProduct is the product the cursor is on in the focused pane.
+func (m *tuiModel) selectedProduct() (string, string, bool) {
+	if m.focus == paneResults {
+		if m.resultIndex < len(m.results) {
+			article := m.results[m.resultIndex]
+			return article.Id, article.Name, article.Id != ""
+		}
+		return "", "", false
+	}
+	items := newMCPCart(m.cart).Items
+	if m.cartIndex < len(items) {
+		return items[m.cartIndex].ID, items[m.cartIndex].Name, true
+	}
+	return "", "", false
+}
+
+func (m *tuiModel) changeCart(change func(string, int) (*picnic.Order, error), id, done string) {
+	if _, err := activeFollowUp(); err != nil {
+		m.status = msg("tui.error", err.Error())
+		return
+	}
+	cart, err := change(id, 1)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.cart = cart
+	m.cartIndex = clampIndex(m.cartIndex, len(newMCPCart(cart).Items))
+	m.status = done
+}
+
+func (m *tuiModel) openSlots() {
+	slots, err := m.store.GetDeliverySlots()
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.slots = slots.DeliverySlots
+	m.slotIndex = 0
+	for i, slot := range m.slots {
+		if slot.Selected || slot.SlotId == slots.SelectedSlot.SlotId {
+			m.slotIndex = i
+			break
+		}
+	}
+	m.status = ""
+	m.focus = paneSlots
+}
+
+func (m *tuiModel) askCheckout() {
+	if m.cart.TotalCount == 0 {
+		m.status = msg("tui.cartEmpty")
+		return
+	}
+	slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: m.cart.DeliverySlots, SelectedSlot: m.cart.SelectedSlot})
+	if !ok {
+		m.status = msg("tui.needSlot")
+		return
+	}
+	m.confirming = true
+	m.status = msg("tui.confirmCheckout", m.cart.TotalCount, amount(m.cart.TotalPrice), formatSlotWindow(slot))
+}
+
+func (m *tuiModel) fail(err error) {
+	if isAuthError(err) {
+		invalidateAuthCache()
+	}
+	m.status = msg("tui.error", err.Error())
+}
+
+// render draws a frame of exactly height lines of width cells.
+func (m *tuiModel) render(width, height int) []string {
+	width = max(width, 40)
+	height = max(height, 10)
+	lines := make([]string, 0, height)
+
+	slotText := msg("tui.noSlot")
+	if slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: m.cart.DeliverySlots, SelectedSlot: m.cart.SelectedSlot}); ok {
+		slotText = formatSlotWindow(slot)
+	}
+	right := msg("tui.header", m.cart.TotalCount, amount(m.cart.TotalPrice), slotText)
+	lines = append(lines, fitCells(fitCells(" Picnic", width-runeWidth(right)-1)+" "+right, width))
+
+	cursor := ""
+	if m.focus == paneSearch {
+		cursor = "_"
+	}
+	lines = append(lines, fitCells(" "+msg("tui.search")+": "+m.query+cursor, width))
+	lines = append(lines, strings.Repeat("─", width))
+
+	bodyHeight := height - len(lines) - 2
+	leftWidth := width * 3 / 5
+	rightWidth := width - leftWidth - 1
+	var left []string
+	if m.focus == paneSlots {
+		left = m.renderSlots(leftWidth, bodyHeight)
+	} else {
+		left = m.renderResults(leftWidth, bodyHeight)
+	}
+	cart := m.renderCart(rightWidth, bodyHeight)
+	for i := 0; i < bodyHeight; i++ {
+		lines = append(lines, fitCells(left[i], leftWidth)+"│"+fitCells(cart[i], rightWidth))
+	}
+
+	lines = append(lines, fitCells(" "+m.status, width))
+	help := msg("tui.help")
+	switch {
+	case m.confirming:
+		help = msg("tui.helpConfirm")
+	case m.focus == paneSearch:
+		help = msg("tui.helpSearch")
+	case m.focus == paneSlots:
+		help = msg("tui.helpSlots")
+	}
+	lines = append(lines, fitCells(" "+help, width))
+	return lines
+}
+
+func (m *tuiModel) renderResults(width, height int) []string {
+	lines := []string{paneTitle(msg("tui.results"), m.focus == paneResults)}
+	switch {
+	case m.searched == "":
+		lines = append(lines, "  "+msg("tui.startSearch"))
+	case len(m.results) == 0:
+		lines = append(lines, "  "+msg("tui.noResults", m.searched))
+	default:
+		perPage := max((height-1)/2, 1)
+		start := max(0, m.resultIndex-perPage+1)
+		for i := start; i < len(m.results) && i < start+perPage; i++ {
+			article := m.results[i]
+			price := article.price().String()
+			marker := "  "
+			if i == m.resultIndex && m.focus == paneResults {
+				marker = "› "
+			}
+			name := fmt.Sprintf("%s%d. %s", marker, i+1, article.Name)
+			lines = append(lines, fitCells(name, width-runeWidth(price)-2)+" "+price+" ")
+
+			var details []string
+			for _, detail := range []string{strings.TrimSpace(article.UnitQuantity), unitPriceText(article), promotionLabel(article)} {
+				if detail != "" {
+					details = append(details, detail)
+				}
+			}
+			lines = append(lines, "     "+strings.Join(details, " · "))
+		}
+	}
+	return padLines(lines, height)
+}
+
+func (m *tuiModel) renderCart(width, height int) []string {
+	items := newMCPCart(m.cart).Items
+	lines := []string{paneTitle(msg("tui.cart"), m.focus == paneCart)}
+	if len(items) == 0 {
+		lines = append(lines, "  "+msg("tui.cartEmpty"))
+	}
+	footer := []string{""}
+	if slot, ok := selectedSlot(&picnic.DeliverySlots{DeliverySlots: m.cart.DeliverySlots, SelectedSlot: m.cart.SelectedSlot}); ok {
+		if gap := slot.MinimumOrderValue - m.cart.TotalPrice; gap > 0 {
+			footer = append(footer, " "+msg("tui.belowMinimum", amount(gap)))
+		}
+	}
+	footer = append(footer, " "+msg("tui.total", amount(m.cart.TotalPrice)))
+
+	perPage := max(height-len(lines)-len(footer), 1)
+	start := max(0, m.cartIndex-perPage+1)
+	for i := start; i < len(items) && i < start+perPage; i++ {
+		item := items[i]
+		marker := "  "
+		if i == m.cartIndex && m.focus == paneCart {
+			marker = "› "
+		}
+		price := amount(item.Price).String()
+		lines = append(lines, fitCells(fmt.Sprintf("%s%dx %s", marker, item.Quantity, item.Name), width-runeWidth(price)-2)+" "+price+" ")
+	}
+	lines = padLines(lines, height-len(footer))
+	return append(lines, footer...)
+}
+
+func (m *tuiModel) renderSlots(width, height int) []string {
+	lines := []string{paneTitle(msg("tui.slots"), true)}
+	if len(m.slots) == 0 {
+		lines = append(lines, "  "+msg("tui.noSlots"))
+	}
+	perPage := max(height-1, 1)
+	start := max(0, m.slotIndex-perPage+1)
+	for i := start; i < len(m.slots) && i < start+perPage; i++ {
+		slot := m.slots[i]
+		marker := "  "
+		if i == m.slotIndex {
+			marker = "› "
+		}
+		var notes []string
+		if slot.MinimumOrderValue > 0 {
+			notes = append(notes, msg("tui.minimum", amount(slot.MinimumOrderValue)))
+		}
+		if slot.Selected || slot.SlotId == m.cart.SelectedSlot.SlotId {
+			notes = append(notes, msg("tui.selected"))
+		}
+		if !slot.IsAvailable {
+			notes = append(notes, msg("tui.unavailable"))
+		}
+		lines = append(lines, fitCells(marker+formatSlotWindow(slot)+"  "+strings.Join(notes, " · "), width))
+	}
+	return padLines(lines, height)
+}
+
+func paneTitle(title string, focused bool) string {
+	if focused {
+		return "[" + title + "]"
+	}
+	return " " + title
+}
+
+// formatSlotWindow shows a slot as e.g. "Fri 21 Oct 18:00-20:00".
+func formatSlotWindow(slot picnic.DeliverySlot) string {
+	start, okStart := parseTimestamp(slot.WindowStart)
+	end, okEnd := parseTimestamp(slot.WindowEnd)
+	if !okStart || !okEnd {
+		return strings.TrimSpace(slot.WindowStart + " - " + slot.WindowEnd)
+	}
+	start, end = start.Local(), end.Local()
+	return start.Format("Mon 02 Jan 15:04") + "-" + end.Format("15:04")
+}
+
+func clampIndex(index, length int) int {
+	if index >= length {
+		index = length - 1
+	}
+	return max(index, 0)
+}
+
+func runeWidth(s string) int {
+	return len([]rune(s))
+}
+
+// fitCells pads or cuts s to width cells, counting one cell per rune.
+func fitCells(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
+
+func padLines(lines []string, height int) []string {
+	if len(lines) > height {
+		return lines[:max(height, 0)]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
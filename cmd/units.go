@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Base units of a unitQuantity.
+const (
+	unitKilogram = "kg"
+	unitLiter    = "l"
+	unitPiece    = "pcs"
+)
+
+var unitQuantityPattern = regexp.MustCompile(`^(?:ca\.?\s*)?(?:(\d+)\s*[x×]\s*)?(\d+(?:[.,]\d+)?)\s*([\p{L}.]+)`)
+
+// unitFactors converts the units Picnic uses in unit quantities to base units.
+var unitFactors = map[string]struct {
+	factor float64
+	unit   string
+}{
+	"mg": {0.000001, unitKilogram}, "g": {0.001, unitKilogram}, "gr": {0.001, unitKilogram},
+	"gram": {0.001, unitKilogram}, "grams": {0.001, unitKilogram}, "gramm": {0.001, unitKilogram},
+	"gramme": {0.001, unitKilogram}, "grammes": {0.001, unitKilogram},
+	"kg": {1, unitKilogram}, "kilo": {1, unitKilogram}, "kilogram": {1, unitKilogram},
+	"ml": {0.001, unitLiter}, "cl": {0.01, unitLiter}, "dl": {0.1, unitLiter},
+	"l": {1, unitLiter}, "ltr": {1, unitLiter}, "liter": {1, unitLiter}, "litre": {1, unitLiter}, "litres": {1, unitLiter},
+	"st": {1, unitPiece}, "st.": {1, unitPiece}, "stuk": {1, unitPiece}, "stuks": {1, unitPiece},
+	"stück": {1, unitPiece}, "stk": {1, unitPiece}, "stk.": {1, unitPiece}, "piece": {1, unitPiece},
+	"pieces": {1, unitPiece}, "pc": {1, unitPiece}, "pcs": {1, unitPiece}, "pièce": {1, unitPiece},
+	"pièces": {1, unitPiece}, "rol": {1, unitPiece}, "rollen": {1, unitPiece}, "eieren": {1, unitPiece},
+	"eggs": {1, unitPiece}, "eier": {1, unitPiece}, "œufs": {1, unitPiece},
+}
+
+// unitQuantity is the content of a pack in kilograms, liters or pieces.
+type unitQuantity struct {
+	Amount float64
+	Unit   string
+}
+
+// parseUnitQuantity reads unit quantities such as "1 liter", "500 gram",
+// "6 x 330 ml" or "ca. 1,2 kg".
+func parseUnitQuantity(value string) (unitQuantity, bool) {
+	m := unitQuantityPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if m == nil {
+		return unitQuantity{}, false
+	}
+	unit, ok := unitFactors[m[3]]
+	if !ok {
+		unit, ok = unitFactors[strings.TrimSuffix(m[3], ".")]
+	}
+	if !ok {
+		return unitQuantity{}, false
+	}
+	size, err := strconv.ParseFloat(strings.Replace(m[2], ",", ".", 1), 64)
+	if err != nil || size <= 0 {
+		return unitQuantity{}, false
+	}
+	count := 1.0
+	if m[1] != "" {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 {
+			return unitQuantity{}, false
+		}
+		count = float64(n)
+	}
+	return unitQuantity{Amount: count * size * unit.factor, Unit: unit.unit}, true
+}
+
+// pricePer is the price in cents per kilogram, liter or piece.
+func (q unitQuantity) pricePer(cents int) int {
+	return int(math.Round(float64(cents) / q.Amount))
+}
+
+// unitPriceText is the price per unit of an article, as Picnic shows it or
+// else computed from the unit quantity.
+func unitPriceText(article searchResult) string {
+	for _, d := range article.Decorators {
+		if text := strings.TrimSpace(d.BasePriceText); text != "" {
+			return text
+		}
+	}
+	price := article.PriceIncludingPromotions()
+	q, ok := parseUnitQuantity(article.UnitQuantity)
+	if !ok || article.PriceMissing || price <= 0 {
+		return ""
+	}
+	return amount(q.pricePer(price)).String() + "/" + msg("units."+q.Unit)
+}
+
+// promotionLabel is the text of an article's promotion, if any.
+func promotionLabel(article searchResult) string {
+	for _, d := range article.Decorators {
+		if d.Type == "PROMO" {
+			return strings.TrimSpace(d.Label)
+		}
+	}
+	return ""
+}
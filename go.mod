@@ -5,9 +5,7 @@ go 1.25.5
 require (
 	github.com/simonmartyr/picnic-api v0.0.0-20240918153231-a083a7fee7a7
 	github.com/spf13/cobra v1.8.1
+	github.com/spf13/pflag v1.0.5
 )
 
-require (
-	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
-)
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect